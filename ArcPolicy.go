@@ -0,0 +1,178 @@
+package LruCache
+
+import "container/list"
+
+// arcNode links a resident entry to its list, T1 or T2.
+type arcNode struct {
+	element *list.Element
+	list    *list.List
+}
+
+// arcPolicy is the Adaptive Replacement Cache policy.
+// T1 holds the entries seen once recently and T2 the entries seen at least twice, the most recently used at the front.
+// B1 and B2 are the ghost lists, they hold the key identity of the entries evicted from T1 and T2, see keyOf.
+// The target size of T1 grows on a B1 ghost hit and shrinks on a B2 ghost hit.
+type arcPolicy struct {
+	t1       *list.List
+	t2       *list.List
+	b1       *list.List
+	b2       *list.List
+	resident map[keyIdentity]*arcNode
+	ghosts   map[keyIdentity]*arcNode
+	target   int
+	capacity int
+}
+
+// OnInsert adds the entry to T2 if its key is a ghost, adapting the T1 target size, or to T1 otherwise.
+func (p *arcPolicy) OnInsert(cacheEntry Entry) {
+	var key = keyOf(cacheEntry.Key())
+	var destination = p.t1
+	if ghost, exists := p.ghosts[key]; exists {
+		p.target = p.adaptedTarget(key)
+		ghost.list.Remove(ghost.element)
+		delete(p.ghosts, key)
+		destination = p.t2
+	}
+	p.resident[key] = &arcNode{element: destination.PushFront(cacheEntry), list: destination}
+	p.trimGhosts()
+}
+
+// OnAccess moves the entry to the front of T2.
+func (p *arcPolicy) OnAccess(cacheEntry Entry) {
+	if node, exists := p.resident[keyOf(cacheEntry.Key())]; exists {
+		node.list.Remove(node.element)
+		node.element = p.t2.PushFront(cacheEntry)
+		node.list = p.t2
+	}
+}
+
+// OnRemove forgets the entry, its key becomes a ghost when it is evicted to make room.
+func (p *arcPolicy) OnRemove(cacheEntry Entry, reason EvictionReason) {
+	var key = keyOf(cacheEntry.Key())
+	var node, exists = p.resident[key]
+	if !exists {
+		return
+	}
+	node.list.Remove(node.element)
+	delete(p.resident, key)
+	if reason == EvictionCapacity || reason == EvictionResize {
+		var ghostList = p.b1
+		if node.list == p.t2 {
+			ghostList = p.b2
+		}
+		p.ghosts[key] = &arcNode{element: ghostList.PushFront(key), list: ghostList}
+		p.trimGhosts()
+	}
+}
+
+// Victim returns the least recently used entry of T1 when T1 exceeds its target size, the one of T2 otherwise.
+// The target size is adapted beforehand if the candidate key is a ghost.
+func (p *arcPolicy) Victim(candidate Entry) Entry {
+	var target = p.target
+	var candidateInB2 bool
+	if candidate != nil {
+		var key = keyOf(candidate.Key())
+		target = p.adaptedTarget(key)
+		if ghost, exists := p.ghosts[key]; exists && ghost.list == p.b2 {
+			candidateInB2 = true
+		}
+	}
+	var t1Len = p.t1.Len()
+	if t1Len > 0 && (t1Len > target || (candidateInB2 && t1Len == target) || p.t2.Len() == 0) {
+		return p.t1.Back().Value.(Entry)
+	} else if p.t2.Len() > 0 {
+		return p.t2.Back().Value.(Entry)
+	} else {
+		return nil
+	}
+}
+
+// adaptedTarget returns the T1 target size after a lookup of the key.
+func (p *arcPolicy) adaptedTarget(key keyIdentity) int {
+	var ghost, exists = p.ghosts[key]
+	if !exists {
+		return p.target
+	}
+	if ghost.list == p.b1 {
+		var delta = 1
+		if p.b1.Len() < p.b2.Len() {
+			delta = p.b2.Len() / p.b1.Len()
+		}
+		return minInt(p.target+delta, p.capacity)
+	} else {
+		var delta = 1
+		if p.b2.Len() < p.b1.Len() {
+			delta = p.b1.Len() / p.b2.Len()
+		}
+		return maxInt(p.target-delta, 0)
+	}
+}
+
+// trimGhosts drops the oldest ghosts, so that T1 and B1 hold at most capacity keys, and all the lists twice the capacity.
+func (p *arcPolicy) trimGhosts() {
+	for p.b1.Len() > 0 && p.t1.Len()+p.b1.Len() > p.capacity {
+		p.dropGhost(p.b1)
+	}
+	for p.b2.Len() > 0 && p.t1.Len()+p.t2.Len()+p.b1.Len()+p.b2.Len() > 2*p.capacity {
+		p.dropGhost(p.b2)
+	}
+	for p.b1.Len() > 0 && p.t1.Len()+p.t2.Len()+p.b1.Len()+p.b2.Len() > 2*p.capacity {
+		p.dropGhost(p.b1)
+	}
+}
+
+// dropGhost removes the oldest ghost of the list.
+func (p *arcPolicy) dropGhost(ghostList *list.List) {
+	delete(p.ghosts, ghostList.Remove(ghostList.Back()).(keyIdentity))
+}
+
+// Resize updates the capacity, the target size is capped and the ghosts trimmed accordingly.
+func (p *arcPolicy) Resize(capacity uint32) {
+	p.capacity = int(capacity)
+	p.target = minInt(p.target, p.capacity)
+	p.trimGhosts()
+}
+
+// Reset forgets all the entries and the ghosts.
+func (p *arcPolicy) Reset() {
+	p.t1 = list.New()
+	p.t2 = list.New()
+	p.b1 = list.New()
+	p.b2 = list.New()
+	p.resident = make(map[keyIdentity]*arcNode, p.capacity)
+	p.ghosts = make(map[keyIdentity]*arcNode, p.capacity)
+	p.target = 0
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// newArcPolicy returns a new ARC eviction policy.
+func newArcPolicy(capacity uint32) EvictionPolicy {
+	var np = &arcPolicy{capacity: int(capacity)}
+	np.Reset()
+	return np
+}
+
+// WithArcEviction makes the cache use the Adaptive Replacement Cache policy, which balances recency and frequency by itself.
+// The policy remembers the keys of as many evicted entries as the cache capacity.
+func WithArcEviction() CacheOption {
+	return WithEvictionPolicy(newArcPolicy)
+}
+
+// NewArcCache returns a new cache able to store size entries, using the Adaptive Replacement Cache policy.
+// The returned cache is not safe for concurrent use, use NewSyncCache with WithArcEviction instead.
+func NewArcCache(size uint32, options ...CacheOption) Cache {
+	return NewCache(size, append(options[:len(options):len(options)], WithArcEviction())...)
+}
@@ -0,0 +1,142 @@
+package LruCache
+
+import (
+	"container/list"
+	"reflect"
+	"testing"
+)
+
+// arcGhostScenario fills a cache of 4 entries with 1 in T2, then 2 to 5 in T1, so that 2 becomes a B1 ghost.
+func arcGhostScenario(c Cache) {
+	c.Add(intEntry(1))
+	c.Get(NewIntKey(1))
+	for k := 2; k <= 5; k++ {
+		c.Add(intEntry(k))
+	}
+}
+
+func Test_arcPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		scenario   func(c Cache)
+		t1, t2     []int
+		b1, b2     []int
+		wantTarget int
+	}{
+		{
+			name: "New entries land in T1",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+			},
+			t1: []int{2, 1}, t2: []int{}, b1: []int{}, b2: []int{},
+		},
+		{
+			name: "A hit promotes the entry to T2",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Get(NewIntKey(1))
+			},
+			t1: []int{2}, t2: []int{1}, b1: []int{}, b2: []int{},
+		},
+		{
+			name:     "T1 evictions become B1 ghosts",
+			scenario: arcGhostScenario,
+			t1:       []int{5, 4, 3}, t2: []int{1}, b1: []int{2}, b2: []int{},
+		},
+		{
+			name: "Without T2 entries, T1 evictions are forgotten",
+			scenario: func(c Cache) {
+				for k := 1; k <= 5; k++ {
+					c.Add(intEntry(k))
+				}
+			},
+			t1: []int{5, 4, 3, 2}, t2: []int{}, b1: []int{}, b2: []int{},
+		},
+		{
+			name: "A B1 ghost hit grows the T1 target and lands in T2",
+			scenario: func(c Cache) {
+				arcGhostScenario(c)
+				c.Add(intEntry(2))
+			},
+			t1: []int{5, 4}, t2: []int{2, 1}, b1: []int{3}, b2: []int{}, wantTarget: 1,
+		},
+		{
+			name: "T2 evictions become B2 ghosts",
+			scenario: func(c Cache) {
+				arcGhostScenario(c)
+				c.Add(intEntry(2))
+				c.Get(NewIntKey(4))
+				c.Get(NewIntKey(5))
+				c.Add(intEntry(6))
+			},
+			t1: []int{6}, t2: []int{5, 4, 2}, b1: []int{3}, b2: []int{1}, wantTarget: 1,
+		},
+		{
+			name: "A B2 ghost hit shrinks the T1 target",
+			scenario: func(c Cache) {
+				arcGhostScenario(c)
+				c.Add(intEntry(2))
+				c.Get(NewIntKey(4))
+				c.Get(NewIntKey(5))
+				c.Add(intEntry(6))
+				c.Add(intEntry(1))
+			},
+			t1: []int{}, t2: []int{1, 5, 4, 2}, b1: []int{6, 3}, b2: []int{}, wantTarget: 0,
+		},
+		{
+			name: "Explicit removals don't leave ghosts",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Get(NewIntKey(2))
+				c.Remove(NewIntKey(1))
+				c.RemoveLruEntry()
+			},
+			t1: []int{}, t2: []int{}, b1: []int{}, b2: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = newCache(4, WithArcEviction())
+			tt.scenario(c)
+			var p = c.policy.(*arcPolicy)
+			for _, l := range []struct {
+				name string
+				list *list.List
+				want []int
+			}{{"T1", p.t1, tt.t1}, {"T2", p.t2, tt.t2}, {"B1", p.b1, tt.b1}, {"B2", p.b2, tt.b2}} {
+				if got := listKeys(l.list); !reflect.DeepEqual(got, l.want) {
+					t.Errorf("%s = %v, want %v", l.name, got, l.want)
+				}
+			}
+			if p.target != tt.wantTarget {
+				t.Errorf("T1 target = %d, want %d", p.target, tt.wantTarget)
+			}
+			if len(p.ghosts) != p.b1.Len()+p.b2.Len() {
+				t.Errorf("%d ghosts indexed, want %d", len(p.ghosts), p.b1.Len()+p.b2.Len())
+			}
+		})
+	}
+}
+
+func Test_arcPolicy_GhostsBound(t *testing.T) {
+	var c = newCache(16, WithArcEviction())
+	var trace = pollutedTrace(2, 20000)
+	replayTrace(c, trace)
+	var p = c.policy.(*arcPolicy)
+	if l := p.t1.Len() + p.b1.Len(); l > 16 {
+		t.Errorf("T1 and B1 hold %d keys, want at most 16", l)
+	}
+	if l := p.t1.Len() + p.t2.Len() + p.b1.Len() + p.b2.Len(); l > 32 {
+		t.Errorf("The ARC lists hold %d keys, want at most 32", l)
+	}
+	c.Resize(4)
+	if l := p.t1.Len() + p.t2.Len() + p.b1.Len() + p.b2.Len(); l > 8 {
+		t.Errorf("The ARC lists hold %d keys after Resize(), want at most 8", l)
+	}
+	if p.target > 4 {
+		t.Errorf("T1 target = %d after Resize(), want at most 4", p.target)
+	}
+}
@@ -1,30 +1,132 @@
 package LruCache
 
-import "container/list"
+import "context"
 
 // cache is a cache object
-type cache struct {
-	cacheMap map[string]Entry
-	cacheLRU *list.List
-	capacity uint32
+// The entries map is indexed by K, the value identify returns for the entry keys: the key identity for the caches
+// returned by NewCache, see keyOf, and the key itself for the typed caches.
+type cache[K comparable] struct {
+	cacheMap     map[K]Entry
+	identify     func(EntryKey) K
+	policy       EvictionPolicy
+	capacity     uint32
+	expiry       *expiryIndex
+	lifetimeHook func(Entry)
+	clock        Clock
+	loadDefaults loadOptions
+	onEvict      func(Entry, EvictionReason)
+	stats        cacheStats
 }
 
-// Add adds a new entry in the cache and returns true if the oldest entry has been removed because the cache was full.
-func (c *cache) Add(cacheEntry Entry) bool {
-	var removeOldest bool
-	if c.Len() == c.capacity {
-		c.RemoveLruEntry()
-		removeOldest = true
+// cacheSettings are the cache settings set by the options.
+type cacheSettings struct {
+	newPolicy    func(capacity uint32) EvictionPolicy
+	clock        Clock
+	loadDefaults loadOptions
+	onEvict      func(Entry, EvictionReason)
+}
+
+// CacheOption is a cache constructor option.
+type CacheOption func(*cacheSettings)
+
+// AddResult describes what happened to the cache when an entry was added.
+type AddResult uint8
+
+const (
+	// EntryInserted means the entry was added without displacing another entry.
+	EntryInserted AddResult = iota
+	// EntryReplaced means the entry replaced an existing entry having the same key.
+	EntryReplaced
+	// LruEntryEvicted means the entry chosen by the eviction policy, the least recently used one by default,
+	// was removed because the cache was full.
+	LruEntryEvicted
+	// EntryRejected means the entry wasn't added because the cache was full and the eviction policy returned no victim,
+	// which is always the case in a cache of capacity 0.
+	EntryRejected
+)
+
+// String returns the add result string representation.
+func (r AddResult) String() string {
+	switch r {
+	case EntryInserted:
+		return "inserted"
+	case EntryReplaced:
+		return "replaced"
+	case LruEntryEvicted:
+		return "evicted"
+	case EntryRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// Add adds a new entry in the cache, or replaces the entry having the same key.
+// It returns what happened and the displaced entry: the replaced entry, the evicted entry, or nil.
+// The entry is rejected when the cache is full and the eviction policy returns no victim.
+// Adding again an entry already in the cache records an access to it, and returns EntryReplaced and no displaced entry.
+func (c *cache[K]) Add(cacheEntry Entry) (AddResult, Entry) {
+	var key = c.identify(cacheEntry.Key())
+	if previousEntry, exists := c.cacheMap[key]; exists {
+		if previousEntry == cacheEntry {
+			cacheEntry.UpdateAccessTime()
+			c.policy.OnAccess(cacheEntry)
+			return EntryReplaced, nil
+		}
+		c.removeEntry(previousEntry, EvictionReplace)
+		c.insertEntry(key, cacheEntry)
+		increment(&c.stats.replacements, 1)
+		return EntryReplaced, previousEntry
+	}
+	var result = EntryInserted
+	var evictedEntry Entry
+	if c.Len() >= c.capacity {
+		if evictedEntry = c.removeVictim(cacheEntry, EvictionCapacity); evictedEntry == nil {
+			return EntryRejected, nil
+		}
+		result = LruEntryEvicted
+		increment(&c.stats.capacityEvictions, 1)
+	}
+	c.insertEntry(key, cacheEntry)
+	increment(&c.stats.inserts, 1)
+	return result, evictedEntry
+}
+
+// insertEntry links the cache entry in the map, the eviction policy and the expiry index.
+func (c *cache[K]) insertEntry(key K, cacheEntry Entry) {
+	c.cacheMap[key] = cacheEntry
+	c.policy.OnInsert(cacheEntry)
+	c.expiry.add(cacheEntry)
+	if ln, ok := cacheEntry.(lifetimeNotifier); ok {
+		ln.setLifetimeHook(c.lifetimeHook)
 	}
-	c.cacheMap[cacheEntry.Key().String()] = cacheEntry
-	cacheEntry.SetLruLink(c.cacheLRU.PushFront(cacheEntry))
-	return removeOldest
 }
 
 // Get returns the entry corresponding to the requested key. It returns nil if the entry doesn't exist or expired.
-func (c *cache) Get(key EntryKey) Entry {
-	if cacheEntry := c.GetWithoutAccessUpdate(key); cacheEntry != nil {
+// It updates the entry last access time and records the hit in the eviction policy,
+// the default policy moves the entry to the front of the LRU list.
+func (c *cache[K]) Get(key EntryKey) Entry {
+	return c.getKey(c.identify(key))
+}
+
+// getKey is Get for the key identity.
+func (c *cache[K]) getKey(key K) Entry {
+	if cacheEntry := c.getKeyWithoutAccessUpdate(key); cacheEntry != nil {
 		cacheEntry.UpdateAccessTime()
+		c.policy.OnAccess(cacheEntry)
+		return cacheEntry
+	} else {
+		return nil
+	}
+}
+
+// getShared returns the valid entry corresponding to the requested key like Get, or nil without counting a miss.
+// It doesn't update the entries map, so it may run concurrently with itself if the eviction policy supports concurrent access.
+func (c *cache[K]) getShared(key K) Entry {
+	if cacheEntry, exists := c.cacheMap[key]; exists && !cacheEntry.IsExpired() {
+		increment(&c.stats.hits, 1)
+		cacheEntry.UpdateAccessTime()
+		c.policy.OnAccess(cacheEntry)
 		return cacheEntry
 	} else {
 		return nil
@@ -33,61 +135,94 @@ func (c *cache) Get(key EntryKey) Entry {
 
 // GetWithoutAccessUpdate returns the entry corresponding to the requested key.
 // It returns true if the entry exists.
-// It doesn't the update the entry last access time.
-func (c *cache) GetWithoutAccessUpdate(key EntryKey) Entry {
-	if cacheEntry, exists := c.cacheMap[key.String()]; exists {
+// It doesn't the update the entry last access time, and doesn't move the entry in the LRU list.
+func (c *cache[K]) GetWithoutAccessUpdate(key EntryKey) Entry {
+	return c.getKeyWithoutAccessUpdate(c.identify(key))
+}
+
+// getKeyWithoutAccessUpdate is GetWithoutAccessUpdate for the key identity.
+func (c *cache[K]) getKeyWithoutAccessUpdate(key K) Entry {
+	if cacheEntry, exists := c.cacheMap[key]; exists {
 		if cacheEntry.IsExpired() {
-			c.Remove(cacheEntry.Key())
+			c.removeEntry(cacheEntry, expiryReason(cacheEntry))
+			increment(&c.stats.expiredOnRead, 1)
+			increment(&c.stats.misses, 1)
 			return nil
 		}
+		increment(&c.stats.hits, 1)
 		return cacheEntry
 	} else {
+		increment(&c.stats.misses, 1)
 		return nil
 	}
 }
 
-// GetLruEntry returns the oldest cache entry.
+// GetLruEntry returns the entry the eviction policy would evict next, the oldest cache entry by default.
 // It doesn't the update the entry last access time.
-func (c *cache) GetLruEntry() Entry {
-	if c.cacheLRU.Len() == 0 {
-		return nil
-	} else {
-		return c.cacheLRU.Back().Value.(Entry)
-	}
+func (c *cache[K]) GetLruEntry() Entry {
+	return c.policy.Victim(nil)
 }
 
 // Contains returns true if the cache contains an entry for the requested key.
-func (c *cache) Contains(key EntryKey) bool {
+func (c *cache[K]) Contains(key EntryKey) bool {
+	return c.containsKey(c.identify(key))
+}
+
+// containsKey is Contains for the key identity.
+func (c *cache[K]) containsKey(key K) bool {
 	var b bool
-	_, b = c.cacheMap[key.String()]
+	_, b = c.cacheMap[key]
 	return b
 }
 
 // Remove removes the cache entry corresponding the requested key.
 // It returns true if the entry exists, and the removed entry.
-func (c *cache) Remove(key EntryKey) (bool, Entry) {
-	if cacheEntry, exists := c.cacheMap[key.String()]; exists {
-		c.cacheLRU.Remove(cacheEntry.GetLruLink())
-		delete(c.cacheMap, key.String())
+func (c *cache[K]) Remove(key EntryKey) (bool, Entry) {
+	return c.removeKey(c.identify(key))
+}
+
+// removeKey is Remove for the key identity.
+func (c *cache[K]) removeKey(key K) (bool, Entry) {
+	if cacheEntry, exists := c.cacheMap[key]; exists {
+		c.removeEntry(cacheEntry, EvictionRemove)
 		return true, cacheEntry
 	} else {
 		return false, nil
 	}
 }
 
-// RemoveLruEntry removes the least recently used cache entry, and returns it.
-func (c *cache) RemoveLruEntry() Entry {
-	var removedEntry Entry = c.GetLruEntry()
+// RemoveLruEntry removes the entry the eviction policy would evict next, the least recently used one by default, and returns it.
+func (c *cache[K]) RemoveLruEntry() Entry {
+	return c.removeVictim(nil, EvictionRemove)
+}
+
+// removeVictim removes the entry chosen by the eviction policy to make room for the candidate for the given reason, and returns it.
+// The candidate is nil when no entry is about to be added.
+func (c *cache[K]) removeVictim(candidate Entry, reason EvictionReason) Entry {
+	var removedEntry Entry = c.policy.Victim(candidate)
 	if removedEntry == nil {
 		return nil
 	} else {
-		_, removedEntry = c.Remove(removedEntry.Key())
+		c.removeEntry(removedEntry, reason)
 		return removedEntry
 	}
 }
 
+// removeEntry unlinks the cache entry from the map, the eviction policy and the expiry index, then notifies the eviction callback.
+func (c *cache[K]) removeEntry(cacheEntry Entry, reason EvictionReason) {
+	c.policy.OnRemove(cacheEntry, reason)
+	delete(c.cacheMap, c.identify(cacheEntry.Key()))
+	c.expiry.remove(cacheEntry)
+	if ln, ok := cacheEntry.(lifetimeNotifier); ok {
+		ln.setLifetimeHook(nil)
+	}
+	if c.onEvict != nil {
+		c.onEvict(cacheEntry, reason)
+	}
+}
+
 // Keys returns the list of cache entries keys.
-func (c *cache) Keys() []EntryKey {
+func (c *cache[K]) Keys() []EntryKey {
 	var ek = make([]EntryKey, c.Len())
 	var idx uint32
 	for _, v := range c.cacheMap {
@@ -98,67 +233,161 @@ func (c *cache) Keys() []EntryKey {
 }
 
 // Len returns the number of entries present in the cache.
-func (c *cache) Len() uint32 {
+func (c *cache[K]) Len() uint32 {
 	return uint32(len(c.cacheMap))
 }
 
 // Capacity returns the cache capacity.
-func (c *cache) Capacity() uint32 {
+func (c *cache[K]) Capacity() uint32 {
 	return c.capacity
 }
 
 // Flush clears the cache and returns the number of entries flushed.
-func (c *cache) Flush() uint32 {
+func (c *cache[K]) Flush() uint32 {
 	var numberOfEntries = c.Len()
-	c.cacheMap = make(map[string]Entry, c.capacity)
-	c.cacheLRU = list.New()
+	for _, cacheEntry := range c.cacheMap {
+		if ln, ok := cacheEntry.(lifetimeNotifier); ok {
+			ln.setLifetimeHook(nil)
+		}
+		if _, ok := c.policy.(ResettablePolicy); !ok {
+			c.policy.OnRemove(cacheEntry, EvictionFlush)
+		}
+		if c.onEvict != nil {
+			c.onEvict(cacheEntry, EvictionFlush)
+		}
+	}
+	c.cacheMap = make(map[K]Entry, c.capacity)
+	if rp, ok := c.policy.(ResettablePolicy); ok {
+		rp.Reset()
+	}
+	c.expiry = newExpiryIndex(c.capacity, c.clock)
+	increment(&c.stats.flushes, 1)
+	increment(&c.stats.flushedEntries, uint64(numberOfEntries))
 	return numberOfEntries
 }
 
 // Resize updates the cache capacity and returns the number of cache entries flushed during the downsizing and the slice of flushed entries.
-func (c *cache) Resize(size uint32) (uint32, []Entry) {
+func (c *cache[K]) Resize(size uint32) (uint32, []Entry) {
 	var flushedEntries = make([]Entry, 0, 0)
-	var numberOfEntries = c.Len()
-	var numberOfDeletion uint32
-	if size < numberOfEntries {
-		numberOfDeletion = numberOfEntries - size
-		flushedEntries = make([]Entry, numberOfDeletion)
-		for i := uint32(0); i < numberOfDeletion; i++ {
-			flushedEntries[i] = c.RemoveLruEntry()
+	for c.Len() > size {
+		var removedEntry = c.removeVictim(nil, EvictionResize)
+		if removedEntry == nil {
+			break
 		}
+		flushedEntries = append(flushedEntries, removedEntry)
 	}
+	var numberOfDeletion = uint32(len(flushedEntries))
+	increment(&c.stats.resizeEvictions, uint64(numberOfDeletion))
 	c.capacity = size
+	if rp, ok := c.policy.(ResizablePolicy); ok {
+		rp.Resize(size)
+	}
 	return numberOfDeletion, flushedEntries
 }
 
 // HouseCleaning triggers the cache cleaning and removes the entries expired.
 // It returns the number of flushed entries and the slice of them.
-func (c *cache) HouseCleaning() (uint32, []Entry) {
+// Only the entries whose expiry deadline is reached are inspected, the entries accessed since
+// they were indexed are indexed again with their new deadline.
+func (c *cache[K]) HouseCleaning() (uint32, []Entry) {
 	var flushedEntry = make([]Entry, 0)
 	var numberOfDeletions uint32
-	for _, cacheEntry := range c.cacheMap {
+	var now = c.clock.Now()
+	for cacheEntry, deadline := c.expiry.next(); cacheEntry != nil && deadline.Before(now); cacheEntry, deadline = c.expiry.next() {
 		if cacheEntry.IsExpired() {
 			flushedEntry = append(flushedEntry, cacheEntry)
-			c.Remove(cacheEntry.Key())
+			c.removeEntry(cacheEntry, expiryReason(cacheEntry))
 			numberOfDeletions++
+		} else {
+			c.expiry.add(cacheEntry)
 		}
 	}
+	increment(&c.stats.houseCleaningRemovals, uint64(numberOfDeletions))
 	return numberOfDeletions, flushedEntry
 }
 
+// reindex updates the expiry deadline of the cache entry after a TTL or max age update.
+func (c *cache[K]) reindex(cacheEntry Entry) {
+	if current, exists := c.cacheMap[c.identify(cacheEntry.Key())]; exists && current == cacheEntry {
+		c.expiry.add(cacheEntry)
+	}
+}
+
 // IsFull returns true if the cache reaches its maximum capacity
-func (c *cache) IsFull() bool {
-	if c.Len() == c.capacity {
+func (c *cache[K]) IsFull() bool {
+	if c.Len() >= c.capacity {
 		return true
 	} else {
 		return false
 	}
 }
 
-func NewCache(size uint32) Cache {
-	var nc = new(cache)
-	nc.cacheMap = make(map[string]Entry, size)
-	nc.cacheLRU = list.New()
+// Stats returns the cache statistics.
+func (c *cache[K]) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// ResetStats sets the cache statistics to zero.
+func (c *cache[K]) ResetStats() {
+	c.stats.reset()
+}
+
+// GetOrLoad returns the entry corresponding to the requested key like Get.
+// On a miss, it calls the loader, adds a new entry holding the loaded value and returns it.
+// The loader error is returned as is and nothing is added to the cache.
+func (c *cache[K]) GetOrLoad(key EntryKey, loader Loader, options ...LoadOption) (Entry, error) {
+	return c.GetOrLoadContext(context.Background(), key, contextLoader(loader), options...)
+}
+
+// GetOrLoadContext is like GetOrLoad, the context is given to the loader.
+// The loader is not called if the context is already done.
+func (c *cache[K]) GetOrLoadContext(ctx context.Context, key EntryKey, loader ContextLoader, options ...LoadOption) (Entry, error) {
+	return getOrLoad(ctx, c, c.loadDefaults, c.clock, key, loader, options)
+}
+
+// loadEntry loads the value of the requested key, adds the new entry in the cache and returns it.
+func (c *cache[K]) loadEntry(ctx context.Context, key EntryKey, loader ContextLoader, options []LoadOption) (Entry, error) {
+	return loadEntry(ctx, c, c.loadDefaults, c.clock, key, loader, options)
+}
+
+// WithClock sets the clock used by the cache to sweep the expired entries.
+// The entries added to the cache are expected to use the same clock, see WithEntryClock.
+func WithClock(clock Clock) CacheOption {
+	return func(s *cacheSettings) {
+		s.clock = clock
+	}
+}
+
+// NewCache returns a new cache able to store size entries.
+// The returned cache is not safe for concurrent use, see NewSyncCache.
+func NewCache(size uint32, options ...CacheOption) Cache {
+	return newCache(size, options...)
+}
+
+// newCache returns a new cache indexed by the key identity, see keyOf.
+func newCache(size uint32, options ...CacheOption) *cache[keyIdentity] {
+	return newKeyedCache(size, keyOf, options)
+}
+
+// newKeyedCache returns a new cache indexed by the value identify returns for the entry keys.
+func newKeyedCache[K comparable](size uint32, identify func(EntryKey) K, options []CacheOption) *cache[K] {
+	var settings = cacheSettings{
+		newPolicy:    newLruPolicy,
+		clock:        SystemClock,
+		loadDefaults: loadOptions{ttl: NoExpiration, maxAge: NoExpiration},
+	}
+	for _, option := range options {
+		option(&settings)
+	}
+	var nc = new(cache[K])
+	nc.cacheMap = make(map[K]Entry, size)
+	nc.identify = identify
+	nc.policy = settings.newPolicy(size)
 	nc.capacity = size
+	nc.expiry = newExpiryIndex(size, settings.clock)
+	nc.lifetimeHook = nc.reindex
+	nc.clock = settings.clock
+	nc.loadDefaults = settings.loadDefaults
+	nc.onEvict = settings.onEvict
 	return nc
 }
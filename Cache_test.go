@@ -4,6 +4,7 @@ import (
 	"container/list"
 	"reflect"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -12,12 +13,20 @@ func Second(i int) time.Duration {
 	return time.Duration(i) * time.Second
 }
 
-func feedLRU(entries ...Entry) *list.List {
+func feedLRU(entries ...Entry) *lruPolicy {
 	var l = list.New()
 	for _, v := range entries {
 		v.SetLruLink(l.PushFront(v))
 	}
-	return l
+	return &lruPolicy{list: l}
+}
+
+func feedExpiry(cacheMap map[keyIdentity]Entry) *expiryIndex {
+	var x = newExpiryIndex(uint32(len(cacheMap)), SystemClock)
+	for _, v := range cacheMap {
+		x.add(v)
+	}
+	return x
 }
 
 func TestNewCache(t *testing.T) {
@@ -38,8 +47,8 @@ func TestNewCache(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := NewCache(tt.args.size)
-			if got.(*cache).capacity != tt.args.size {
-				t.Errorf("New cache capacity = %d, want %d", got.(*cache).capacity, tt.want)
+			if got.(*cache[keyIdentity]).capacity != tt.args.size {
+				t.Errorf("New cache capacity = %d, want %d", got.(*cache[keyIdentity]).capacity, tt.want)
 			}
 		})
 	}
@@ -47,8 +56,8 @@ func TestNewCache(t *testing.T) {
 
 func Test_cache_Add(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -67,83 +76,124 @@ func Test_cache_Add(t *testing.T) {
 	var e4a = NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15))
 	var e4aBis = NewEntry(NewStringKey("A"), "A entry, new version", Second(10), Second(15))
 
+	var e5a = NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15))
+	var e5b = NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15))
+	var e5c = NewEntry(NewStringKey("C"), "C entry", Second(10), Second(15))
+	var e5d = NewEntry(NewStringKey("D"), "D entry", Second(10), Second(15))
+	var e5aBis = NewEntry(NewStringKey("A"), "A entry, new version", Second(10), Second(15))
+
 	tests := []struct {
 		name   string
 		fields fields
 		args   Entry
-		want   bool
+		want   AddResult
+		want1  Entry
+		want2  uint32
 	}{
 		{
 			name: "Add an entry in an empty cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry, 16),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry, 16),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
-			args: e1a,
-			want: false,
+			args:  e1a,
+			want:  EntryInserted,
+			want1: nil,
+			want2: 1,
 		},
 		{
 			name: "Add an entry in a non-empty cache",
 			fields: fields{
-				cacheMap: map[string]Entry{
-					"A": e2a,
-					"B": e2b,
+				cacheMap: map[keyIdentity]Entry{
+					keyOf(NewStringKey("A")): e2a,
+					keyOf(NewStringKey("B")): e2b,
 				},
-				cacheLRU: feedLRU(e2a, e2b),
+				policy:   feedLRU(e2a, e2b),
 				capacity: 16,
 			},
-			args: e2c,
-			want: false,
+			args:  e2c,
+			want:  EntryInserted,
+			want1: nil,
+			want2: 3,
 		},
 		{
 			name: "Add an entry in a full cache",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e3a, "B": e3b, "C": e3c, "D": e3d},
-				cacheLRU: feedLRU(e3a, e3b, e3c, e3d),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e3a, keyOf(NewStringKey("B")): e3b, keyOf(NewStringKey("C")): e3c, keyOf(NewStringKey("D")): e3d},
+				policy:   feedLRU(e3a, e3b, e3c, e3d),
 				capacity: 4,
 			},
-			args: e3e,
-			want: true,
+			args:  e3e,
+			want:  LruEntryEvicted,
+			want1: e3a,
+			want2: 4,
 		},
 		{
 			name: "Overwrite an entry in the cache",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e4a},
-				cacheLRU: feedLRU(e4a),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e4a},
+				policy:   feedLRU(e4a),
 				capacity: 16,
 			},
-			args: e4aBis,
-			want: false,
+			args:  e4aBis,
+			want:  EntryReplaced,
+			want1: e4a,
+			want2: 1,
+		},
+		{
+			name: "Overwrite an entry in a full cache",
+			fields: fields{
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e5a, keyOf(NewStringKey("B")): e5b, keyOf(NewStringKey("C")): e5c, keyOf(NewStringKey("D")): e5d},
+				policy:   feedLRU(e5a, e5b, e5c, e5d),
+				capacity: 4,
+			},
+			args:  e5aBis,
+			want:  EntryReplaced,
+			want1: e5a,
+			want2: 4,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
-			got := c.Add(tt.args)
+			got, got1 := c.Add(tt.args)
 			// Check the position in the lru cache
-			if tt.args.GetLruLink() != c.cacheLRU.Front() {
+			if tt.args.GetLruLink() != c.policy.(*lruPolicy).list.Front() {
 				t.Error("The new cache entry should be the first entry in the LRU cache, and it is not.")
 			}
 			// Check it is present in the cache
-			if _, exist := c.cacheMap[tt.args.Key().String()]; !exist {
+			if _, exist := c.cacheMap[keyOf(tt.args.Key())]; !exist {
 				t.Error("The new cache entry was not found in the cache.")
 			}
-			// Check if the LRU entry was removed when cache is full
+			// Check if the LRU entry was removed when cache is full, or the previous entry replaced
 			if got != tt.want {
-				t.Errorf("Got %t in return instead of %t", got, tt.want)
+				t.Errorf("Got %s in return instead of %s", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("Got %v as displaced entry instead of %v", got1, tt.want1)
+			}
+			// Check the map and the LRU list are consistent
+			if l := c.Len(); l != tt.want2 {
+				t.Errorf("Cache len = %d, want %d", l, tt.want2)
+			}
+			if l := uint32(c.policy.(*lruPolicy).list.Len()); l != tt.want2 {
+				t.Errorf("LRU list len = %d, want %d", l, tt.want2)
 			}
 			// Check is not expired
 			if tt.args.IsExpired() {
 				t.Error("New cache entry is expired and it should not.")
 			}
 			// Check entry value
-			if tt.args.Value() != c.cacheMap[tt.args.Key().String()].Value() {
-				t.Errorf("Wrong cache entry value, got (%v) instead of (%s).", tt.args.Value(), c.cacheMap[tt.args.Key().String()].Value())
+			if tt.args.Value() != c.cacheMap[keyOf(tt.args.Key())].Value() {
+				t.Errorf("Wrong cache entry value, got (%v) instead of (%s).", tt.args.Value(), c.cacheMap[keyOf(tt.args.Key())].Value())
 			}
 		})
 	}
@@ -151,8 +201,8 @@ func Test_cache_Add(t *testing.T) {
 
 func Test_cache_Capacity(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 	tests := []struct {
@@ -164,7 +214,7 @@ func Test_cache_Capacity(t *testing.T) {
 			name: "Get cache capacity",
 			fields: fields{
 				cacheMap: nil,
-				cacheLRU: nil,
+				policy:   nil,
 				capacity: 16,
 			},
 			want: 16,
@@ -172,10 +222,13 @@ func Test_cache_Capacity(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			if got := c.Capacity(); got != tt.want {
 				t.Errorf("Capacity() = %v, want %v", got, tt.want)
@@ -186,8 +239,8 @@ func Test_cache_Capacity(t *testing.T) {
 
 func Test_cache_Contains(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 	tests := []struct {
@@ -199,11 +252,11 @@ func Test_cache_Contains(t *testing.T) {
 		{
 			name: "The cache is supposed to contain A",
 			fields: fields{
-				cacheMap: map[string]Entry{
-					"A": NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)),
-					"B": NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)),
+				cacheMap: map[keyIdentity]Entry{
+					keyOf(NewStringKey("A")): NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)),
+					keyOf(NewStringKey("B")): NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)),
 				},
-				cacheLRU: nil,
+				policy:   nil,
 				capacity: 16,
 			},
 			args: NewStringKey("A"),
@@ -212,11 +265,11 @@ func Test_cache_Contains(t *testing.T) {
 		{
 			name: "The cache is not supposed to contain D",
 			fields: fields{
-				cacheMap: map[string]Entry{
-					"A": NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)),
-					"B": NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)),
+				cacheMap: map[keyIdentity]Entry{
+					keyOf(NewStringKey("A")): NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)),
+					keyOf(NewStringKey("B")): NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)),
 				},
-				cacheLRU: nil,
+				policy:   nil,
 				capacity: 16,
 			},
 			args: NewStringKey("D"),
@@ -225,8 +278,8 @@ func Test_cache_Contains(t *testing.T) {
 		{
 			name: "The cache is empty",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: nil,
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   nil,
 				capacity: 16,
 			},
 			args: NewStringKey("A"),
@@ -235,10 +288,13 @@ func Test_cache_Contains(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			if got := c.Contains(tt.args); got != tt.want {
 				t.Errorf("Contains() = %v, want %v", got, tt.want)
@@ -249,8 +305,8 @@ func Test_cache_Contains(t *testing.T) {
 
 func Test_cache_Flush(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -267,13 +323,13 @@ func Test_cache_Flush(t *testing.T) {
 		{
 			name: "Flush a non-empty cache",
 			fields: fields{
-				cacheMap: map[string]Entry{
-					"A": e1a,
-					"B": e1b,
-					"C": e1c,
-					"D": e1d,
+				cacheMap: map[keyIdentity]Entry{
+					keyOf(NewStringKey("A")): e1a,
+					keyOf(NewStringKey("B")): e1b,
+					keyOf(NewStringKey("C")): e1c,
+					keyOf(NewStringKey("D")): e1d,
 				},
-				cacheLRU: feedLRU(e1a, e1b, e1c, e1d),
+				policy:   feedLRU(e1a, e1b, e1c, e1d),
 				capacity: 16,
 			},
 			want: 4,
@@ -281,8 +337,8 @@ func Test_cache_Flush(t *testing.T) {
 		{
 			name: "Flush an empty cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
 			want: 0,
@@ -290,13 +346,13 @@ func Test_cache_Flush(t *testing.T) {
 		{
 			name: "Flush a cache full",
 			fields: fields{
-				cacheMap: map[string]Entry{
-					"A": e1a,
-					"B": e1b,
-					"C": e1c,
-					"D": e1d,
+				cacheMap: map[keyIdentity]Entry{
+					keyOf(NewStringKey("A")): e1a,
+					keyOf(NewStringKey("B")): e1b,
+					keyOf(NewStringKey("C")): e1c,
+					keyOf(NewStringKey("D")): e1d,
 				},
-				cacheLRU: feedLRU(e1a, e1b, e1c, e1d),
+				policy:   feedLRU(e1a, e1b, e1c, e1d),
 				capacity: 4,
 			},
 			want: 4,
@@ -304,10 +360,13 @@ func Test_cache_Flush(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			got := c.Flush()
 			if got != tt.want {
@@ -316,7 +375,7 @@ func Test_cache_Flush(t *testing.T) {
 			if l := len(c.cacheMap); l != 0 {
 				t.Errorf("Cache map is not flushed, len = %d", l)
 			}
-			if l := c.cacheLRU.Len(); l != 0 {
+			if l := c.policy.(*lruPolicy).list.Len(); l != 0 {
 				t.Errorf("LRU cache is not flushed, len = %d", l)
 			}
 		})
@@ -325,8 +384,8 @@ func Test_cache_Flush(t *testing.T) {
 
 func Test_cache_Get(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 	var e1a = NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15))
@@ -344,11 +403,11 @@ func Test_cache_Get(t *testing.T) {
 		{
 			name: "Get an existing entry",
 			fields: fields{
-				cacheMap: map[string]Entry{
-					"A": e1a,
-					"B": e1b,
+				cacheMap: map[keyIdentity]Entry{
+					keyOf(NewStringKey("A")): e1a,
+					keyOf(NewStringKey("B")): e1b,
 				},
-				cacheLRU: feedLRU(e1a, e1b),
+				policy:   feedLRU(e1a, e1b),
 				capacity: 16,
 			},
 			args: e1a.Key(),
@@ -357,11 +416,11 @@ func Test_cache_Get(t *testing.T) {
 		{
 			name: "Get a non-existing entry",
 			fields: fields{
-				cacheMap: map[string]Entry{
-					"A": e2a,
-					"B": e2b,
+				cacheMap: map[keyIdentity]Entry{
+					keyOf(NewStringKey("A")): e2a,
+					keyOf(NewStringKey("B")): e2b,
 				},
-				cacheLRU: feedLRU(e2a, e2b),
+				policy:   feedLRU(e2a, e2b),
 				capacity: 16,
 			},
 			args: NewStringKey("C"),
@@ -370,10 +429,13 @@ func Test_cache_Get(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			got := c.Get(tt.args)
 			if !reflect.DeepEqual(got, tt.want) {
@@ -383,15 +445,110 @@ func Test_cache_Get(t *testing.T) {
 				if got.(*entry).creationTime.Sub(got.(*entry).accessTime) == 0 {
 					t.Error("Entry access time not updated")
 				}
+				if got.GetLruLink() != c.policy.(*lruPolicy).list.Front() {
+					t.Error("The entry should be the first entry in the LRU cache, and it is not.")
+				}
+			}
+		})
+	}
+}
+
+func Test_cache_LruOrder(t *testing.T) {
+	type access struct {
+		key     string
+		promote bool
+	}
+	tests := []struct {
+		name     string
+		keys     []string
+		accesses []access
+		want     []string
+	}{
+		{
+			name:     "Without access the eviction order is the insertion order",
+			keys:     []string{"A", "B", "C", "D"},
+			accesses: nil,
+			want:     []string{"A", "B", "C", "D"},
+		},
+		{
+			name:     "Get promotes the entry",
+			keys:     []string{"A", "B", "C", "D"},
+			accesses: []access{{key: "A", promote: true}, {key: "C", promote: true}},
+			want:     []string{"B", "D", "A", "C"},
+		},
+		{
+			name:     "Get promotes the entry each time it is called",
+			keys:     []string{"A", "B", "C", "D"},
+			accesses: []access{{key: "A", promote: true}, {key: "B", promote: true}, {key: "A", promote: true}},
+			want:     []string{"C", "D", "B", "A"},
+		},
+		{
+			name:     "GetWithoutAccessUpdate doesn't promote the entry",
+			keys:     []string{"A", "B", "C", "D"},
+			accesses: []access{{key: "A", promote: false}, {key: "B", promote: false}},
+			want:     []string{"A", "B", "C", "D"},
+		},
+		{
+			name:     "Mix Get and GetWithoutAccessUpdate",
+			keys:     []string{"A", "B", "C", "D"},
+			accesses: []access{{key: "B", promote: true}, {key: "A", promote: false}, {key: "D", promote: false}},
+			want:     []string{"A", "C", "D", "B"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCache(uint32(len(tt.keys)))
+			for _, k := range tt.keys {
+				c.Add(NewEntry(NewStringKey(k), k+" entry", Second(10), Second(15)))
+			}
+			for _, a := range tt.accesses {
+				var got Entry
+				if a.promote {
+					got = c.Get(NewStringKey(a.key))
+				} else {
+					got = c.GetWithoutAccessUpdate(NewStringKey(a.key))
+				}
+				if got == nil {
+					t.Fatalf("Entry %s not found", a.key)
+				}
+			}
+			if got := c.GetLruEntry(); got == nil || got.Key().String() != tt.want[0] {
+				t.Errorf("GetLruEntry() = %v, want %s", got, tt.want[0])
+			}
+			for _, want := range tt.want {
+				got := c.RemoveLruEntry()
+				if got == nil {
+					t.Fatalf("RemoveLruEntry() = nil, want %s", want)
+				}
+				if got.Key().String() != want {
+					t.Errorf("RemoveLruEntry() = %s, want %s", got.Key().String(), want)
+				}
 			}
 		})
 	}
 }
 
+func Test_cache_LruEvictionOnAdd(t *testing.T) {
+	var c = NewCache(3)
+	c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)))
+	c.Add(NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)))
+	c.Add(NewEntry(NewStringKey("C"), "C entry", Second(10), Second(15)))
+	c.Get(NewStringKey("A"))
+	c.Add(NewEntry(NewStringKey("D"), "D entry", Second(10), Second(15)))
+	if c.Contains(NewStringKey("B")) {
+		t.Error("B is the least recently used entry and should have been evicted")
+	}
+	for _, k := range []string{"A", "C", "D"} {
+		if !c.Contains(NewStringKey(k)) {
+			t.Errorf("%s should still be in the cache", k)
+		}
+	}
+}
+
 func Test_cache_GetLruEntry(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -408,8 +565,8 @@ func Test_cache_GetLruEntry(t *testing.T) {
 		{
 			name: "Get LRU entry",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e1a, "B": e1b},
-				cacheLRU: feedLRU(e1a, e1b),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e1a, keyOf(NewStringKey("B")): e1b},
+				policy:   feedLRU(e1a, e1b),
 				capacity: 16,
 			},
 			want: e1a,
@@ -417,8 +574,8 @@ func Test_cache_GetLruEntry(t *testing.T) {
 		{
 			name: "Get LRU entry",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e2a},
-				cacheLRU: feedLRU(e2a),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e2a},
+				policy:   feedLRU(e2a),
 				capacity: 16,
 			},
 			want: e2a,
@@ -426,8 +583,8 @@ func Test_cache_GetLruEntry(t *testing.T) {
 		{
 			name: "Get LRU entry",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
 			want: nil,
@@ -435,10 +592,13 @@ func Test_cache_GetLruEntry(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			if got := c.GetLruEntry(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetLruEntry() = %v, want %v", got, tt.want)
@@ -449,8 +609,8 @@ func Test_cache_GetLruEntry(t *testing.T) {
 
 func Test_cache_GetWithoutAccessUpdate(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -477,8 +637,8 @@ func Test_cache_GetWithoutAccessUpdate(t *testing.T) {
 		{
 			name: "Get an existing entry",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e1a, "B": e1b},
-				cacheLRU: nil,
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e1a, keyOf(NewStringKey("B")): e1b},
+				policy:   nil,
 				capacity: 16,
 			},
 			args: e1a.Key(),
@@ -487,8 +647,8 @@ func Test_cache_GetWithoutAccessUpdate(t *testing.T) {
 		{
 			name: "Get a non-existing entry",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: nil,
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   nil,
 				capacity: 16,
 			},
 			args: e1a.Key(),
@@ -497,8 +657,8 @@ func Test_cache_GetWithoutAccessUpdate(t *testing.T) {
 		{
 			name: "Get an existing entry which is expired",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e3a, "B": e3b},
-				cacheLRU: feedLRU(e3a, e3b),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e3a, keyOf(NewStringKey("B")): e3b},
+				policy:   feedLRU(e3a, e3b),
 				capacity: 16,
 			},
 			args: e3a.Key(),
@@ -507,10 +667,13 @@ func Test_cache_GetWithoutAccessUpdate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			got := c.GetWithoutAccessUpdate(tt.args)
 			if !reflect.DeepEqual(got, tt.want) {
@@ -527,8 +690,8 @@ func Test_cache_GetWithoutAccessUpdate(t *testing.T) {
 
 func Test_cache_HouseCleaning(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -587,8 +750,8 @@ func Test_cache_HouseCleaning(t *testing.T) {
 		{
 			name: "Clean the cache",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e1a, "B": e1b, "C": e1c, "D": e1d, "E": e1e},
-				cacheLRU: feedLRU(e1a, e1b, e1c, e1d, e1e),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e1a, keyOf(NewStringKey("B")): e1b, keyOf(NewStringKey("C")): e1c, keyOf(NewStringKey("D")): e1d, keyOf(NewStringKey("E")): e1e},
+				policy:   feedLRU(e1a, e1b, e1c, e1d, e1e),
 				capacity: 16,
 			},
 			want:  3,
@@ -597,10 +760,13 @@ func Test_cache_HouseCleaning(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			got, got1 := c.HouseCleaning()
 			if got != tt.want {
@@ -621,8 +787,8 @@ func Test_cache_HouseCleaning(t *testing.T) {
 
 func Test_cache_Keys(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -637,8 +803,8 @@ func Test_cache_Keys(t *testing.T) {
 		{
 			name: "Get keys for a non-empty cache",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e1a, "B": e1b},
-				cacheLRU: feedLRU(e1a, e1b),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e1a, keyOf(NewStringKey("B")): e1b},
+				policy:   feedLRU(e1a, e1b),
 				capacity: 16,
 			},
 			want: []EntryKey{e1a.Key(), e1b.Key()},
@@ -646,8 +812,8 @@ func Test_cache_Keys(t *testing.T) {
 		{
 			name: "Get keys for an empty cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
 			want: []EntryKey{},
@@ -655,10 +821,13 @@ func Test_cache_Keys(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			got := c.Keys()
 			sort.Slice(got, func(i, j int) bool {
@@ -676,8 +845,8 @@ func Test_cache_Keys(t *testing.T) {
 
 func Test_cache_Len(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -692,8 +861,8 @@ func Test_cache_Len(t *testing.T) {
 		{
 			name: "Get size of a non-empty cache",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e1a, "B": e1b},
-				cacheLRU: feedLRU(e1a, e1b),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e1a, keyOf(NewStringKey("B")): e1b},
+				policy:   feedLRU(e1a, e1b),
 				capacity: 16,
 			},
 			want: 2,
@@ -701,8 +870,8 @@ func Test_cache_Len(t *testing.T) {
 		{
 			name: "Get size of an empty cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
 			want: 0,
@@ -710,10 +879,13 @@ func Test_cache_Len(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			if got := c.Len(); got != tt.want {
 				t.Errorf("Len() = %v, want %v", got, tt.want)
@@ -724,8 +896,8 @@ func Test_cache_Len(t *testing.T) {
 
 func Test_cache_Remove(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -745,8 +917,8 @@ func Test_cache_Remove(t *testing.T) {
 		{
 			name: "Remove an existing entry",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e1a, "B": e1b},
-				cacheLRU: feedLRU(e1a, e1b),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e1a, keyOf(NewStringKey("B")): e1b},
+				policy:   feedLRU(e1a, e1b),
 				capacity: 16,
 			},
 			args:  e1b.Key(),
@@ -756,8 +928,8 @@ func Test_cache_Remove(t *testing.T) {
 		{
 			name: "Remove a non-existing entry from a non-empty cache",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e2a, "B": e2b},
-				cacheLRU: feedLRU(e2a, e2b),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e2a, keyOf(NewStringKey("B")): e2b},
+				policy:   feedLRU(e2a, e2b),
 				capacity: 16,
 			},
 			args:  NewStringKey("C"),
@@ -767,8 +939,8 @@ func Test_cache_Remove(t *testing.T) {
 		{
 			name: "Remove a non-existing entry from an empty cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
 			args:  NewStringKey("A"),
@@ -778,10 +950,13 @@ func Test_cache_Remove(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			got, got1 := c.Remove(tt.args)
 			if got != tt.want {
@@ -796,8 +971,8 @@ func Test_cache_Remove(t *testing.T) {
 
 func Test_cache_RemoveLruEntry(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 	var e1a = NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15))
@@ -811,8 +986,8 @@ func Test_cache_RemoveLruEntry(t *testing.T) {
 		{
 			name: "Get LRU entry from a non-empty cache",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e1a, "B": e1b},
-				cacheLRU: feedLRU(e1a, e1b),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e1a, keyOf(NewStringKey("B")): e1b},
+				policy:   feedLRU(e1a, e1b),
 				capacity: 0,
 			},
 			want: e1a,
@@ -820,8 +995,8 @@ func Test_cache_RemoveLruEntry(t *testing.T) {
 		{
 			name: "Get LRU entry from an empty cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 0,
 			},
 			want: nil,
@@ -829,10 +1004,13 @@ func Test_cache_RemoveLruEntry(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			got := c.RemoveLruEntry()
 			if !reflect.DeepEqual(got, tt.want) {
@@ -844,8 +1022,8 @@ func Test_cache_RemoveLruEntry(t *testing.T) {
 
 func Test_cache_Resize(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -869,8 +1047,8 @@ func Test_cache_Resize(t *testing.T) {
 		{
 			name: "Upsize a cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
 			args:  16,
@@ -880,8 +1058,8 @@ func Test_cache_Resize(t *testing.T) {
 		{
 			name: "Upsize a cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
 			args:  32,
@@ -891,8 +1069,8 @@ func Test_cache_Resize(t *testing.T) {
 		{
 			name: "Downsize an empty cache",
 			fields: fields{
-				cacheMap: make(map[string]Entry),
-				cacheLRU: list.New(),
+				cacheMap: make(map[keyIdentity]Entry),
+				policy:   newLruPolicy(0),
 				capacity: 16,
 			},
 			args:  8,
@@ -902,8 +1080,8 @@ func Test_cache_Resize(t *testing.T) {
 		{
 			name: "Downsize a non-empty cache with enough free room",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e4a, "B": e4b, "C": e4c, "D": e4d},
-				cacheLRU: feedLRU(e4a, e4b, e4c, e4d),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e4a, keyOf(NewStringKey("B")): e4b, keyOf(NewStringKey("C")): e4c, keyOf(NewStringKey("D")): e4d},
+				policy:   feedLRU(e4a, e4b, e4c, e4d),
 				capacity: 16,
 			},
 			args:  8,
@@ -913,8 +1091,8 @@ func Test_cache_Resize(t *testing.T) {
 		{
 			name: "Downsize a non-empty cache with not enough free room",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e5a, "B": e5b, "C": e5c, "D": e5d},
-				cacheLRU: feedLRU(e5a, e5b, e5c, e5d),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e5a, keyOf(NewStringKey("B")): e5b, keyOf(NewStringKey("C")): e5c, keyOf(NewStringKey("D")): e5d},
+				policy:   feedLRU(e5a, e5b, e5c, e5d),
 				capacity: 16,
 			},
 			args:  2,
@@ -924,10 +1102,13 @@ func Test_cache_Resize(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 			got, got1 := c.Resize(tt.args)
 			if got != tt.want {
@@ -942,8 +1123,8 @@ func Test_cache_Resize(t *testing.T) {
 
 func Test_cache_IsFull(t *testing.T) {
 	type fields struct {
-		cacheMap map[string]Entry
-		cacheLRU *list.List
+		cacheMap map[keyIdentity]Entry
+		policy   EvictionPolicy
 		capacity uint32
 	}
 
@@ -965,8 +1146,8 @@ func Test_cache_IsFull(t *testing.T) {
 		{
 			name: "Cache is full",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e1a, "B": e1b, "C": e1c, "D": e1d},
-				cacheLRU: feedLRU(e1a, e1b, e1c, e1d),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e1a, keyOf(NewStringKey("B")): e1b, keyOf(NewStringKey("C")): e1c, keyOf(NewStringKey("D")): e1d},
+				policy:   feedLRU(e1a, e1b, e1c, e1d),
 				capacity: 4,
 			},
 			want: true,
@@ -974,8 +1155,8 @@ func Test_cache_IsFull(t *testing.T) {
 		{
 			name: "Cache is not full",
 			fields: fields{
-				cacheMap: map[string]Entry{"A": e2a, "B": e2b, "C": e2c, "D": e2d},
-				cacheLRU: feedLRU(e2a, e2b, e2c, e2d),
+				cacheMap: map[keyIdentity]Entry{keyOf(NewStringKey("A")): e2a, keyOf(NewStringKey("B")): e2b, keyOf(NewStringKey("C")): e2c, keyOf(NewStringKey("D")): e2d},
+				policy:   feedLRU(e2a, e2b, e2c, e2d),
 				capacity: 16,
 			},
 			want: false,
@@ -983,10 +1164,13 @@ func Test_cache_IsFull(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c := &cache{
+			c := &cache[keyIdentity]{
+				identify: keyOf,
 				cacheMap: tt.fields.cacheMap,
-				cacheLRU: tt.fields.cacheLRU,
+				policy:   tt.fields.policy,
 				capacity: tt.fields.capacity,
+				expiry:   feedExpiry(tt.fields.cacheMap),
+				clock:    SystemClock,
 			}
 
 			if got := c.IsFull(); got != tt.want {
@@ -995,3 +1179,70 @@ func Test_cache_IsFull(t *testing.T) {
 		})
 	}
 }
+
+func Test_cache_KeyTypeCollisions(t *testing.T) {
+	var c = NewCache(16)
+	var intEntry = NewEntry(NewIntKey(1), "int entry", Second(10), Second(15))
+	var stringEntry = NewEntry(NewStringKey("1"), "string entry", Second(10), Second(15))
+
+	if got, _ := c.Add(intEntry); got != EntryInserted {
+		t.Errorf("Add() = %s, want %s", got, EntryInserted)
+	}
+	if got, _ := c.Add(stringEntry); got != EntryInserted {
+		t.Errorf("Add() = %s, want %s, the string key overwrote the int key", got, EntryInserted)
+	}
+	if got := c.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+	if got := c.Get(NewIntKey(1)); got != intEntry {
+		t.Errorf("Get(NewIntKey(1)) = %v, want %v", got, intEntry)
+	}
+	if got := c.Get(NewStringKey("1")); got != stringEntry {
+		t.Errorf("Get(NewStringKey(\"1\")) = %v, want %v", got, stringEntry)
+	}
+	if got, got1 := c.Remove(NewIntKey(1)); !got || got1 != intEntry {
+		t.Errorf("Remove(NewIntKey(1)) = %t, %v, want true, %v", got, got1, intEntry)
+	}
+	if !c.Contains(NewStringKey("1")) {
+		t.Error("Removing the int key removed the string key")
+	}
+}
+
+func Test_cache_LookupAllocations(t *testing.T) {
+	var c = NewCache(16)
+	var stringKey = NewStringKey("A")
+	var intKey = NewIntKey(1 << 20)
+	var stringEntry = NewEntry(stringKey, "A entry", NoExpiration, NoExpiration)
+	c.Add(stringEntry)
+	c.Add(NewEntry(intKey, "int entry", NoExpiration, NoExpiration))
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{name: "Get with a string key", f: func() { c.Get(stringKey) }},
+		{name: "Get with an int key", f: func() { c.Get(intKey) }},
+		{name: "Contains", f: func() { c.Contains(intKey) }},
+		{name: "Add of the same entry", f: func() { c.Add(stringEntry) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testing.AllocsPerRun(100, tt.f); got != 0 {
+				t.Errorf("%s allocates %.0f times, want 0", tt.name, got)
+			}
+		})
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	var c = NewCache(1024)
+	var keys = make([]EntryKey, 1024)
+	for i := range keys {
+		keys[i] = NewStringKey(strconv.Itoa(i))
+		c.Add(NewEntry(keys[i], i, NoExpiration, NoExpiration))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(keys[i%len(keys)])
+	}
+}
@@ -0,0 +1,14 @@
+package LruCache
+
+import "time"
+
+// systemClock is the default clock, it returns time.Now().
+type systemClock struct{}
+
+// Now returns the current time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the clock used by default by the entries and the caches.
+var SystemClock Clock = systemClock{}
@@ -0,0 +1,132 @@
+package LruCache
+
+import "sync/atomic"
+
+// clockSlot is a ring slot, the slot is free when its entry is nil.
+type clockSlot struct {
+	cacheEntry Entry
+	referenced uint32
+}
+
+// clockPolicy is the CLOCK policy, a second-chance approximation of LRU.
+// The entries are stored in a ring of slots with a reference bit set on access.
+// The hand sweeps the ring, clearing the reference bits, until it finds an entry not referenced since the last sweep.
+// The access only sets the reference bit with an atomic operation, so that it may run concurrently with itself.
+type clockPolicy struct {
+	slots []clockSlot
+	index map[keyIdentity]int
+	free  []int
+	hand  int
+}
+
+// OnInsert puts the entry in a free slot, or in a new slot, with its reference bit cleared.
+func (p *clockPolicy) OnInsert(cacheEntry Entry) {
+	var i int
+	if l := len(p.free); l > 0 {
+		i = p.free[l-1]
+		p.free = p.free[:l-1]
+	} else {
+		i = len(p.slots)
+		p.slots = append(p.slots, clockSlot{})
+	}
+	p.slots[i] = clockSlot{cacheEntry: cacheEntry}
+	p.index[keyOf(cacheEntry.Key())] = i
+}
+
+// OnAccess sets the reference bit of the entry.
+func (p *clockPolicy) OnAccess(cacheEntry Entry) {
+	if i, exists := p.index[keyOf(cacheEntry.Key())]; exists {
+		atomic.StoreUint32(&p.slots[i].referenced, 1)
+	}
+}
+
+// concurrentAccess marks OnAccess as safe for concurrent use.
+func (p *clockPolicy) concurrentAccess() {}
+
+// OnRemove frees the slot of the entry, the hand moves past it so that the next entry added there is swept last.
+func (p *clockPolicy) OnRemove(cacheEntry Entry, _ EvictionReason) {
+	var key = keyOf(cacheEntry.Key())
+	var i, exists = p.index[key]
+	if !exists {
+		return
+	}
+	p.slots[i] = clockSlot{}
+	p.free = append(p.free, i)
+	delete(p.index, key)
+	if i == p.hand {
+		p.advance()
+	}
+}
+
+// advance moves the hand to the next slot.
+func (p *clockPolicy) advance() {
+	p.hand++
+	if p.hand >= len(p.slots) {
+		p.hand = 0
+	}
+}
+
+// Victim sweeps the ring from the hand, giving a second chance to the referenced entries,
+// and returns the first entry not referenced. The hand stays on the returned entry.
+func (p *clockPolicy) Victim(_ Entry) Entry {
+	if len(p.index) == 0 {
+		return nil
+	}
+	for {
+		var slot = &p.slots[p.hand]
+		if slot.cacheEntry != nil {
+			if atomic.LoadUint32(&slot.referenced) == 0 {
+				return slot.cacheEntry
+			}
+			atomic.StoreUint32(&slot.referenced, 0)
+		}
+		p.advance()
+	}
+}
+
+// Resize compacts the ring when it holds more slots than the capacity, keeping the order of the entries from the hand.
+func (p *clockPolicy) Resize(capacity uint32) {
+	if len(p.slots) <= int(capacity) {
+		return
+	}
+	var slots = make([]clockSlot, 0, capacity)
+	for n := 0; n < len(p.slots); n++ {
+		var slot = p.slots[(p.hand+n)%len(p.slots)]
+		if slot.cacheEntry != nil {
+			p.index[keyOf(slot.cacheEntry.Key())] = len(slots)
+			slots = append(slots, slot)
+		}
+	}
+	p.slots = slots
+	p.free = p.free[:0]
+	p.hand = 0
+}
+
+// Reset forgets all the entries.
+func (p *clockPolicy) Reset() {
+	p.slots = make([]clockSlot, 0, cap(p.slots))
+	p.index = make(map[keyIdentity]int, cap(p.slots))
+	p.free = nil
+	p.hand = 0
+}
+
+// newClockPolicy returns a new CLOCK eviction policy.
+func newClockPolicy(capacity uint32) EvictionPolicy {
+	return &clockPolicy{
+		slots: make([]clockSlot, 0, capacity),
+		index: make(map[keyIdentity]int, capacity),
+	}
+}
+
+// WithClockEviction makes the cache use the CLOCK policy, a second-chance approximation of LRU:
+// a hit only sets a reference bit instead of moving the entry in a list.
+// The caches returned by NewSyncCache and NewShardedCache record the hits under their read lock with this policy.
+func WithClockEviction() CacheOption {
+	return WithEvictionPolicy(newClockPolicy)
+}
+
+// NewClockCache returns a new cache able to store size entries, using the CLOCK policy.
+// The returned cache is not safe for concurrent use, use NewSyncCache with WithClockEviction instead.
+func NewClockCache(size uint32, options ...CacheOption) Cache {
+	return NewCache(size, append(options[:len(options):len(options)], WithClockEviction())...)
+}
@@ -0,0 +1,105 @@
+package LruCache
+
+import (
+	"reflect"
+	"testing"
+)
+
+// clockKeys returns the keys of the ring slots from the hand, 0 standing for a free slot, and their reference bits.
+func clockKeys(p *clockPolicy) ([]int, []uint32) {
+	var keys = make([]int, 0, len(p.slots))
+	var bits = make([]uint32, 0, len(p.slots))
+	for n := 0; n < len(p.slots); n++ {
+		var slot = p.slots[(p.hand+n)%len(p.slots)]
+		if slot.cacheEntry == nil {
+			keys = append(keys, 0)
+		} else {
+			keys = append(keys, slot.cacheEntry.Value().(int))
+		}
+		bits = append(bits, slot.referenced)
+	}
+	return keys, bits
+}
+
+func Test_clockPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario func(c Cache)
+		keys     []int
+		bits     []uint32
+	}{
+		{
+			name: "A hit sets the reference bit",
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(2))
+				c.GetWithoutAccessUpdate(NewIntKey(3))
+			},
+			keys: []int{1, 2, 3, 4},
+			bits: []uint32{0, 1, 0, 0},
+		},
+		{
+			name: "The referenced entries get a second chance",
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(3))
+				c.Add(intEntry(5))
+				c.Add(intEntry(6))
+			},
+			keys: []int{1, 5, 3, 6},
+			bits: []uint32{0, 0, 0, 0},
+		},
+		{
+			name: "Looking at the victim doesn't change it",
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.GetLruEntry()
+				c.GetLruEntry()
+			},
+			keys: []int{2, 3, 4, 1},
+			bits: []uint32{0, 0, 0, 0},
+		},
+		{
+			name: "Downsizing compacts the ring",
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Remove(NewIntKey(2))
+				c.Get(NewIntKey(3))
+				c.Resize(2)
+			},
+			keys: []int{3, 4},
+			bits: []uint32{1, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = newCache(4, WithClockEviction())
+			tt.scenario(c)
+			var keys, bits = clockKeys(c.policy.(*clockPolicy))
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("Ring from the hand = %v, want %v", keys, tt.keys)
+			}
+			if !reflect.DeepEqual(bits, tt.bits) {
+				t.Errorf("Reference bits from the hand = %v, want %v", bits, tt.bits)
+			}
+		})
+	}
+}
+
+func BenchmarkClockCache_Trace(b *testing.B) {
+	benchmarkTrace(b, func(size uint32) Cache { return NewClockCache(size) })
+}
+
+func BenchmarkClockSyncCache_Parallel(b *testing.B) {
+	benchmarkParallelCache(b, NewSyncCache(4096, WithClockEviction()))
+}
@@ -0,0 +1,134 @@
+package LruCache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrLoaderPanic is wrapped by the error returned by a CoalescingLoader when the loader panicked.
+var ErrLoaderPanic = errors.New("the loader panicked")
+
+// CoalescingStats are the statistics of a CoalescingLoader.
+type CoalescingStats struct {
+	// Loads is the number of loader calls.
+	Loads uint64
+	// Deduplicated is the number of misses which waited for the loader call of another caller instead of calling the loader.
+	Deduplicated uint64
+}
+
+// flight is an in-flight loader call shared by the callers waiting for it.
+type flight struct {
+	done    chan struct{}
+	entry   Entry
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// coalescingLoader is a read-through loader sharing one loader call between the concurrent misses of the same key.
+// The atomic counters come first to be 64-bit aligned on the 32-bit platforms.
+type coalescingLoader struct {
+	loads        uint64
+	deduplicated uint64
+	cache        Cache
+	mu           sync.Mutex
+	flights      map[keyIdentity]*flight
+}
+
+// GetOrLoad returns the entry corresponding to the requested key like Cache.GetOrLoad.
+// On a miss, the callers requesting the same key share the result of one loader call.
+func (cl *coalescingLoader) GetOrLoad(key EntryKey, loader Loader, options ...LoadOption) (Entry, error) {
+	return cl.GetOrLoadContext(context.Background(), key, contextLoader(loader), options...)
+}
+
+// GetOrLoadContext is like GetOrLoad, each caller stops waiting when its context is done.
+// The loader context is cancelled once every waiting caller gave up, it doesn't carry the callers context values.
+// The load options of the caller starting the loader call are used.
+func (cl *coalescingLoader) GetOrLoadContext(ctx context.Context, key EntryKey, loader ContextLoader, options ...LoadOption) (Entry, error) {
+	if cacheEntry := cl.cache.Get(key); cacheEntry != nil {
+		return cacheEntry, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var id = keyOf(key)
+	cl.mu.Lock()
+	var f, exists = cl.flights[id]
+	if exists {
+		f.waiters++
+		atomic.AddUint64(&cl.deduplicated, 1)
+	} else {
+		var flightCtx, cancel = context.WithCancel(context.Background())
+		f = &flight{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		cl.flights[id] = f
+		atomic.AddUint64(&cl.loads, 1)
+		go cl.load(flightCtx, id, f, key, loader, options)
+	}
+	cl.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.entry, f.err
+	case <-ctx.Done():
+		cl.leave(id, f)
+		return nil, ctx.Err()
+	}
+}
+
+// load calls the loader through the cache and publishes the result to the flight waiters.
+// A loader panic is recovered and returned to the waiters as an error wrapping ErrLoaderPanic.
+// The miss is already counted by the caller starting the flight, so the entry is loaded without a new lookup
+// when the cache supports it.
+func (cl *coalescingLoader) load(ctx context.Context, id keyIdentity, f *flight, key EntryKey, loader ContextLoader, options []LoadOption) {
+	defer f.cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			f.entry, f.err = nil, fmt.Errorf("%w: %v", ErrLoaderPanic, r)
+		}
+		cl.mu.Lock()
+		if cl.flights[id] == f {
+			delete(cl.flights, id)
+		}
+		cl.mu.Unlock()
+		close(f.done)
+	}()
+	if el, ok := cl.cache.(entryLoader); ok {
+		f.entry, f.err = el.loadEntry(ctx, key, loader, options)
+	} else {
+		f.entry, f.err = cl.cache.GetOrLoadContext(ctx, key, loader, options...)
+	}
+}
+
+// leave removes a waiter from the flight, the last waiter cancels the loader call.
+// The abandoned flight is forgotten so that the next miss starts a new loader call.
+func (cl *coalescingLoader) leave(id keyIdentity, f *flight) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	f.waiters--
+	if f.waiters == 0 {
+		f.cancel()
+		if cl.flights[id] == f {
+			delete(cl.flights, id)
+		}
+	}
+}
+
+// Stats returns the number of loader calls and the number of deduplicated calls.
+func (cl *coalescingLoader) Stats() CoalescingStats {
+	return CoalescingStats{
+		Loads:        atomic.LoadUint64(&cl.loads),
+		Deduplicated: atomic.LoadUint64(&cl.deduplicated),
+	}
+}
+
+// NewCoalescingLoader returns a read-through loader populating the cache c, which must be safe for concurrent use,
+// see NewSyncCache and NewShardedCache.
+func NewCoalescingLoader(c Cache) CoalescingLoader {
+	var ncl = new(coalescingLoader)
+	ncl.cache = c
+	ncl.flights = make(map[keyIdentity]*flight)
+	return ncl
+}
@@ -0,0 +1,201 @@
+package LruCache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForDeduplicated waits until the loader counted n deduplicated calls.
+func waitForDeduplicated(t *testing.T, cl CoalescingLoader, n uint64) {
+	var timeout = time.After(time.Second)
+	for cl.Stats().Deduplicated < n {
+		select {
+		case <-timeout:
+			t.Fatalf("Deduplicated = %d, want %d", cl.Stats().Deduplicated, n)
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestCoalescingLoader_GetOrLoad(t *testing.T) {
+	const callers = 32
+	var c = NewSyncCache(16)
+	var cl = NewCoalescingLoader(c)
+	var release = make(chan struct{})
+	var calls int32
+	var loader = func(EntryKey) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "loaded", nil
+	}
+
+	var wg sync.WaitGroup
+	var results = make([]Entry, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			if results[i], err = cl.GetOrLoad(NewStringKey("A"), loader); err != nil {
+				t.Errorf("GetOrLoad() error = %v", err)
+			}
+		}(i)
+	}
+	waitForDeduplicated(t, cl, callers-1)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Loader called %d times, want 1", got)
+	}
+	for i := range results {
+		if results[i] == nil || results[i] != results[0] {
+			t.Fatalf("Caller %d got %v, want %v", i, results[i], results[0])
+		}
+	}
+	if got := (CoalescingStats{Loads: 1, Deduplicated: callers - 1}); cl.Stats() != got {
+		t.Errorf("Stats() = %+v, want %+v", cl.Stats(), got)
+	}
+	if s := c.Stats(); s.Misses != callers || s.Hits != 0 || s.Inserts != 1 {
+		t.Errorf("Cache Stats() = %+v, want %d misses, no hit and 1 insert", s, callers)
+	}
+	if c.Get(NewStringKey("A")) != results[0] {
+		t.Error("The loaded entry was not added to the cache")
+	}
+
+	// A hit doesn't call the loader
+	if got, _ := cl.GetOrLoad(NewStringKey("A"), loader); got != results[0] || cl.Stats().Loads != 1 {
+		t.Errorf("GetOrLoad() = %v, Loads = %d on a hit", got, cl.Stats().Loads)
+	}
+}
+
+func TestCoalescingLoader_Error(t *testing.T) {
+	var errLoad = errors.New("load error")
+	var cl = NewCoalescingLoader(NewSyncCache(16))
+	var release = make(chan struct{})
+	var loader = func(EntryKey) (interface{}, error) {
+		<-release
+		return nil, errLoad
+	}
+	var errs = make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := cl.GetOrLoad(NewIntKey(1), loader)
+			errs <- err
+		}()
+	}
+	waitForDeduplicated(t, cl, 1)
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, errLoad) {
+			t.Errorf("GetOrLoad() error = %v, want %v", err, errLoad)
+		}
+	}
+	// The error is not cached, the next miss calls the loader again
+	if _, err := cl.GetOrLoad(NewIntKey(1), func(EntryKey) (interface{}, error) { return 1, nil }); err != nil {
+		t.Errorf("GetOrLoad() error = %v, want nil", err)
+	}
+	if got := cl.Stats().Loads; got != 2 {
+		t.Errorf("Loads = %d, want 2", got)
+	}
+}
+
+func TestCoalescingLoader_Panic(t *testing.T) {
+	var c = NewSyncCache(16)
+	var cl = NewCoalescingLoader(c)
+	var release = make(chan struct{})
+	var loader = func(EntryKey) (interface{}, error) {
+		<-release
+		panic("boom")
+	}
+	var errs = make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := cl.GetOrLoad(NewIntKey(1), loader)
+			errs <- err
+		}()
+	}
+	waitForDeduplicated(t, cl, 1)
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, ErrLoaderPanic) || !strings.Contains(err.Error(), "boom") {
+			t.Errorf("GetOrLoad() error = %v, want %v with the panic value", err, ErrLoaderPanic)
+		}
+	}
+	if c.Contains(NewIntKey(1)) {
+		t.Error("An entry was added by the panicking loader")
+	}
+	// The flight is forgotten, the next miss calls the loader again
+	if got, err := cl.GetOrLoad(NewIntKey(1), func(EntryKey) (interface{}, error) { return 1, nil }); err != nil || got == nil {
+		t.Errorf("GetOrLoad() = %v, %v, want the loaded entry", got, err)
+	}
+}
+
+func TestCoalescingLoader_GetOrLoadContext(t *testing.T) {
+	var cl = NewCoalescingLoader(NewSyncCache(16))
+	var release = make(chan struct{})
+	var loaderCtx = make(chan context.Context, 1)
+	var loader = func(ctx context.Context, _ EntryKey) (interface{}, error) {
+		loaderCtx <- ctx
+		select {
+		case <-release:
+			return "loaded", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// The first caller gives up, the second one still receives the loaded value
+	var ctx1, cancel1 = context.WithCancel(context.Background())
+	var err1 = make(chan error, 1)
+	go func() {
+		_, err := cl.GetOrLoadContext(ctx1, NewIntKey(1), loader)
+		err1 <- err
+	}()
+	var lctx = <-loaderCtx
+	var result2 = make(chan Entry, 1)
+	go func() {
+		e, _ := cl.GetOrLoadContext(context.Background(), NewIntKey(1), loader)
+		result2 <- e
+	}()
+	waitForDeduplicated(t, cl, 1)
+	cancel1()
+	if err := <-err1; !errors.Is(err, context.Canceled) {
+		t.Errorf("GetOrLoadContext() error = %v, want %v", err, context.Canceled)
+	}
+	if lctx.Err() != nil {
+		t.Error("The loader context should not be cancelled while a caller is waiting")
+	}
+	close(release)
+	if e := <-result2; e == nil || e.Value() != "loaded" {
+		t.Errorf("GetOrLoadContext() = %v, want loaded", e)
+	}
+
+	// The loader context is cancelled when every caller gave up
+	var ctx3, cancel3 = context.WithCancel(context.Background())
+	var err3 = make(chan error, 1)
+	go func() {
+		_, err := cl.GetOrLoadContext(ctx3, NewIntKey(3), func(ctx context.Context, _ EntryKey) (interface{}, error) {
+			loaderCtx <- ctx
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		err3 <- err
+	}()
+	lctx = <-loaderCtx
+	cancel3()
+	if err := <-err3; !errors.Is(err, context.Canceled) {
+		t.Errorf("GetOrLoadContext() error = %v, want %v", err, context.Canceled)
+	}
+	select {
+	case <-lctx.Done():
+	case <-time.After(time.Second):
+		t.Error("The loader context was not cancelled after every caller gave up")
+	}
+}
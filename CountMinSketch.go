@@ -0,0 +1,85 @@
+package LruCache
+
+const (
+	// sketchDepth is the number of counter rows of the count-min sketch.
+	sketchDepth = 4
+	// sketchMaxCount is the value the counters saturate at, like 4 bits counters.
+	sketchMaxCount = 15
+	// sketchMaxWidth is the maximum number of counters per row, 64 MiB of counters in total.
+	// The sample size, 10 times the width, fits in an uint32.
+	sketchMaxWidth = 1 << 24
+)
+
+// sketchSeeds are the seeds mixed with the key hash to index each row.
+var sketchSeeds = [sketchDepth]uint32{0x97cb3127, 0xc2b2ae35, 0x85ebca6b, 0x27d4eb2f}
+
+// countMinSketch estimates the access frequency of the keys from their hash, with saturating counters.
+// All the counters are halved once the number of increments reaches the sample size, so that the old accesses fade away.
+type countMinSketch struct {
+	rows       [sketchDepth][]uint8
+	mask       uint32
+	additions  uint32
+	sampleSize uint32
+}
+
+// index returns the counter index of the hash in the row.
+func (s *countMinSketch) index(hash uint32, row int) uint32 {
+	var h = (hash ^ sketchSeeds[row]) * 0x9e3779b1
+	h ^= h >> 16
+	return h & s.mask
+}
+
+// increment records an access to the key having the hash.
+func (s *countMinSketch) increment(hash uint32) {
+	for row := range s.rows {
+		var i = s.index(hash, row)
+		if s.rows[row][i] < sketchMaxCount {
+			s.rows[row][i]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.sampleSize {
+		s.age()
+	}
+}
+
+// estimate returns the estimated access frequency of the key having the hash.
+func (s *countMinSketch) estimate(hash uint32) uint8 {
+	var frequency uint8 = sketchMaxCount
+	for row := range s.rows {
+		if count := s.rows[row][s.index(hash, row)]; count < frequency {
+			frequency = count
+		}
+	}
+	return frequency
+}
+
+// age halves all the counters.
+func (s *countMinSketch) age() {
+	for row := range s.rows {
+		for i := range s.rows[row] {
+			s.rows[row][i] >>= 1
+		}
+	}
+	s.additions /= 2
+}
+
+// countMinSketchWidth returns the number of counters per row of a sketch sized for capacity keys,
+// the power of two above capacity between 16 and sketchMaxWidth.
+func countMinSketchWidth(capacity uint32) uint32 {
+	var width uint32 = 16
+	for width < capacity && width < sketchMaxWidth {
+		width <<= 1
+	}
+	return width
+}
+
+// newCountMinSketch returns a sketch sized for capacity keys.
+func newCountMinSketch(capacity uint32) *countMinSketch {
+	var width = countMinSketchWidth(capacity)
+	var ns = &countMinSketch{mask: width - 1, sampleSize: 10 * width}
+	for row := range ns.rows {
+		ns.rows[row] = make([]uint8, width)
+	}
+	return ns
+}
@@ -0,0 +1,108 @@
+package LruCache
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func Test_countMinSketch(t *testing.T) {
+	var s = newCountMinSketch(64)
+	var hot, cold, unseen = fnv32a("hot"), fnv32a("cold"), fnv32a("unseen")
+	for i := 0; i < 6; i++ {
+		s.increment(hot)
+	}
+	s.increment(cold)
+	if got := s.estimate(hot); got != 6 {
+		t.Errorf("estimate() = %d for the hot key, want 6", got)
+	}
+	if got := s.estimate(cold); got != 1 {
+		t.Errorf("estimate() = %d for the cold key, want 1", got)
+	}
+	if got := s.estimate(unseen); got != 0 {
+		t.Errorf("estimate() = %d for an unseen key, want 0", got)
+	}
+	for i := 0; i < 2*sketchMaxCount; i++ {
+		s.increment(hot)
+	}
+	if got := s.estimate(hot); got != sketchMaxCount {
+		t.Errorf("estimate() = %d for a saturated key, want %d", got, sketchMaxCount)
+	}
+}
+
+func Test_countMinSketch_Aging(t *testing.T) {
+	var s = newCountMinSketch(16)
+	if s.sampleSize != 160 {
+		t.Fatalf("Sample size = %d, want 160", s.sampleSize)
+	}
+	var hot = fnv32a("hot")
+	for i := 0; i < 12; i++ {
+		s.increment(hot)
+	}
+	// Filling the sample with other keys halves the hot key frequency
+	for i := uint32(12); i < s.sampleSize; i++ {
+		s.increment(fnv32a(strconv.Itoa(int(i))))
+	}
+	if got := s.estimate(hot); got < 6 || got > 7 {
+		t.Errorf("estimate() = %d after aging, want 6 or 7", got)
+	}
+	if s.additions != s.sampleSize/2 {
+		t.Errorf("%d additions after aging, want %d", s.additions, s.sampleSize/2)
+	}
+}
+
+func Test_newCountMinSketch(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity uint32
+		want     int
+	}{
+		{name: "Minimum width", capacity: 1, want: 16},
+		{name: "Power of two", capacity: 1024, want: 1024},
+		{name: "Rounded up", capacity: 1000, want: 1024},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s = newCountMinSketch(tt.capacity)
+			for row := range s.rows {
+				if got := len(s.rows[row]); got != tt.want {
+					t.Errorf("Row %d width = %d, want %d", row, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_countMinSketchWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity uint32
+		want     uint32
+	}{
+		{name: "Minimum width", capacity: 0, want: 16},
+		{name: "Rounded up", capacity: 1000, want: 1024},
+		{name: "Maximum width", capacity: sketchMaxWidth + 1, want: sketchMaxWidth},
+		{name: "Maximum capacity", capacity: math.MaxUint32, want: sketchMaxWidth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countMinSketchWidth(tt.capacity); got != tt.want {
+				t.Errorf("countMinSketchWidth(%d) = %d, want %d", tt.capacity, got, tt.want)
+			}
+			if sampleSize := 10 * uint64(tt.want); sampleSize > math.MaxUint32 {
+				t.Errorf("Sample size = %d for a width of %d, overflows an uint32", sampleSize, tt.want)
+			}
+		})
+	}
+}
+
+// fnv32a returns the 32 bits FNV-1a hash of s.
+func fnv32a(s string) uint32 {
+	const offset32, prime32 = 2166136261, 16777619
+	var h uint32 = offset32
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= prime32
+	}
+	return h
+}
@@ -2,10 +2,15 @@ package LruCache
 
 import (
 	"container/list"
+	"sync"
 	"time"
 )
 
+// entry is a cache entry object.
+// The TTL, the max age and the last access time are protected by mu because an entry
+// can be read and accessed from several goroutines once it is returned by a shared cache.
 type entry struct {
+	mu           sync.RWMutex
 	key          EntryKey
 	value        interface{}
 	ttl          time.Duration
@@ -13,6 +18,45 @@ type entry struct {
 	accessTime   time.Time
 	creationTime time.Time
 	lruElement   *list.Element
+	lifetimeHook func(Entry)
+	clock        Clock
+}
+
+// NoExpiration is the TTL or max age value disabling the entry expiry on this criterion.
+// Any TTL or max age lower than or equal to zero disables the expiry the same way.
+// It is also the delay returned by GetDelayToTTL, GetDelayToMaxAge and GetDurationBeforeFlush
+// when the entry doesn't expire.
+const NoExpiration time.Duration = -1
+
+// EntryOption is an entry constructor option.
+type EntryOption func(*entry)
+
+// WithEntryClock sets the clock used by the entry to compute its age and its expiry.
+func WithEntryClock(clock Clock) EntryOption {
+	return func(e *entry) {
+		e.clock = clock
+	}
+}
+
+// now returns the current time according to the entry clock.
+func (e *entry) now() time.Time {
+	if e.clock == nil {
+		return time.Now()
+	}
+	return e.clock.Now()
+}
+
+// lifetimeNotifier is implemented by the entries able to notify the cache holding them when
+// their TTL or max age is updated.
+type lifetimeNotifier interface {
+	setLifetimeHook(hook func(Entry))
+}
+
+// setLifetimeHook sets the function called after each TTL or max age update, nil removes it.
+func (e *entry) setLifetimeHook(hook func(Entry)) {
+	e.mu.Lock()
+	e.lifetimeHook = hook
+	e.mu.Unlock()
 }
 
 // SetLruLink sets the link between the cache entry the LRU entry list.
@@ -31,7 +75,8 @@ func (e *entry) Key() EntryKey {
 	return e.key
 }
 
-// Value returns the entry value.
+// Value returns the entry value and updates the entry last access time.
+// It doesn't move the entry in the cache LRU list, use Cache.Get for that.
 func (e *entry) Value() interface{} {
 	e.UpdateAccessTime()
 	return e.value
@@ -39,47 +84,72 @@ func (e *entry) Value() interface{} {
 
 // SetTTL sets the entry TTL.
 func (e *entry) SetTTL(ttl time.Duration) {
+	e.mu.Lock()
 	e.ttl = ttl
+	var hook = e.lifetimeHook
+	e.mu.Unlock()
+	if hook != nil {
+		hook(e)
+	}
 }
 
 // GetTTL returns the entry TTL value.
 func (e *entry) GetTTL() time.Duration {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return e.ttl
 }
 
 // SetMaxAge set the entry max age to maxAge.
 func (e *entry) SetMaxAge(maxAge time.Duration) {
+	e.mu.Lock()
 	e.maxAge = maxAge
+	var hook = e.lifetimeHook
+	e.mu.Unlock()
+	if hook != nil {
+		hook(e)
+	}
 }
 
 // GetMaxAge returns the entry max age.
 func (e *entry) GetMaxAge() time.Duration {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return e.maxAge
 }
 
-// UpdateAccessTime updates the entry last access time to time.Now()
+// UpdateAccessTime updates the entry last access time to the entry clock current time.
 func (e *entry) UpdateAccessTime() {
-	e.accessTime = time.Now()
+	e.mu.Lock()
+	e.accessTime = e.now()
+	e.mu.Unlock()
 }
 
 // GetAccessTime returns the entry last access time.
 func (e *entry) GetAccessTime() time.Time {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return e.accessTime
 }
 
 // GetAge returns the entry age.
 func (e *entry) GetAge() time.Duration {
-	return time.Now().Sub(e.creationTime)
+	return e.now().Sub(e.creationTime)
 }
 
 // GetElapsedTimeFromLastAccess returns the elapsed from the entry last access time.
 func (e *entry) GetElapsedTimeFromLastAccess() time.Duration {
-	return time.Now().Sub(e.accessTime)
+	return e.now().Sub(e.GetAccessTime())
 }
 
 // GetDelayToTTL returns the remaining delay before to reach the entry ttl.
+// It returns NoExpiration if the entry has no TTL.
 func (e *entry) GetDelayToTTL() time.Duration {
-	var dtt time.Duration = e.ttl - e.GetElapsedTimeFromLastAccess()
+	var ttl = e.GetTTL()
+	if ttl <= 0 {
+		return NoExpiration
+	}
+	var dtt time.Duration = ttl - e.GetElapsedTimeFromLastAccess()
 	if dtt < 0 {
 		return 0
 	} else {
@@ -88,8 +158,13 @@ func (e *entry) GetDelayToTTL() time.Duration {
 }
 
 // GetDelayToMaxAge returns the remaining delay before to reach the entry max age.
+// It returns NoExpiration if the entry has no max age.
 func (e *entry) GetDelayToMaxAge() time.Duration {
-	var dtma time.Duration = e.maxAge - e.GetAge()
+	var maxAge = e.GetMaxAge()
+	if maxAge <= 0 {
+		return NoExpiration
+	}
+	var dtma time.Duration = maxAge - e.GetAge()
 	if dtma < 0 {
 		return 0
 	} else {
@@ -98,20 +173,28 @@ func (e *entry) GetDelayToMaxAge() time.Duration {
 }
 
 // GetDurationBeforeFlush returns the remaining entry lifetime.
+// It returns NoExpiration if the entry has neither TTL nor max age.
 func (e *entry) GetDurationBeforeFlush() time.Duration {
 	var dtt, dtma time.Duration
 	dtt = e.GetDelayToTTL()
 	dtma = e.GetDelayToMaxAge()
-	if dtt < dtma {
+	switch {
+	case dtt == NoExpiration:
+		return dtma
+	case dtma == NoExpiration:
 		return dtt
-	} else {
+	case dtt < dtma:
+		return dtt
+	default:
 		return dtma
 	}
 }
 
 // ExceedTTL returns true if the entry reached the TTL.
+// An entry without TTL never reaches it.
 func (e *entry) ExceedTTL() bool {
-	if e.GetElapsedTimeFromLastAccess() > e.GetTTL() {
+	var ttl = e.GetTTL()
+	if ttl > 0 && e.GetElapsedTimeFromLastAccess() > ttl {
 		return true
 	} else {
 		return false
@@ -119,8 +202,10 @@ func (e *entry) ExceedTTL() bool {
 }
 
 // ExceedMaxAge return true if the entry reached the max age.
+// An entry without max age never reaches it.
 func (e *entry) ExceedMaxAge() bool {
-	if e.GetAge() > e.GetMaxAge() {
+	var maxAge = e.GetMaxAge()
+	if maxAge > 0 && e.GetAge() > maxAge {
 		return true
 	} else {
 		return false
@@ -139,13 +224,19 @@ func (e *entry) IsExpired() bool {
 	}
 }
 
-func NewEntry(key EntryKey, value interface{}, ttl time.Duration, maxAge time.Duration) Entry {
+// NewEntry returns a new cache entry.
+// The entry uses the SystemClock unless the WithEntryClock option is given.
+func NewEntry(key EntryKey, value interface{}, ttl time.Duration, maxAge time.Duration, options ...EntryOption) Entry {
 	var ne = new(entry)
 	ne.key = key
 	ne.value = value
 	ne.ttl = ttl
 	ne.maxAge = maxAge
-	ne.creationTime = time.Now()
+	ne.clock = SystemClock
+	for _, option := range options {
+		option(ne)
+	}
+	ne.creationTime = ne.now()
 	ne.accessTime = ne.creationTime
 	return ne
 }
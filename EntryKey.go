@@ -12,7 +12,12 @@ func (ek *entryStringKey) String() string {
 	return string(*ek)
 }
 
-// NewStringKey returns a new key struct implementing the EntryKey interface
+// Identity returns the key identity, distinct from the identity of an int key having the same string representation
+func (ek *entryStringKey) Identity() interface{} {
+	return *ek
+}
+
+// NewStringKey returns a new key struct implementing the EntryKey and TypedKey interfaces
 func NewStringKey(key string) EntryKey {
 	var k = new(entryStringKey)
 	*k = entryStringKey(key)
@@ -21,7 +26,7 @@ func NewStringKey(key string) EntryKey {
 
 // INTEGER KEY
 
-// entryStringKey is the private type representing a string key
+// entryIntKey is the private type representing an int key
 type entryIntKey int
 
 // String returns the key string representation
@@ -29,9 +34,50 @@ func (ek *entryIntKey) String() string {
 	return strconv.Itoa(int(*ek))
 }
 
-// NewIntKey returns a new key struct implementing the EntryKey interface
+// Identity returns the key identity, distinct from the identity of a string key having the same string representation
+func (ek *entryIntKey) Identity() interface{} {
+	return *ek
+}
+
+// NewIntKey returns a new key struct implementing the EntryKey and TypedKey interfaces
 func NewIntKey(key int) EntryKey {
 	var k = new(entryIntKey)
 	*k = entryIntKey(key)
 	return k
 }
+
+// KEY IDENTITY
+
+// keyKind tells how a key identity is built.
+type keyKind uint8
+
+const (
+	keyKindString keyKind = iota
+	keyKindStringKey
+	keyKindIntKey
+	keyKindTypedKey
+)
+
+// keyIdentity is the comparable value indexing a key in the caches and the eviction policies.
+// The built-in keys are stored by value in s or i, so that indexing them doesn't allocate.
+type keyIdentity struct {
+	kind     keyKind
+	s        string
+	i        int
+	identity interface{}
+}
+
+// keyOf returns the value used to index the key in the caches.
+// It is the key identity for a TypedKey, and the key string representation for the other keys.
+func keyOf(key EntryKey) keyIdentity {
+	switch k := key.(type) {
+	case *entryStringKey:
+		return keyIdentity{kind: keyKindStringKey, s: string(*k)}
+	case *entryIntKey:
+		return keyIdentity{kind: keyKindIntKey, i: int(*k)}
+	case TypedKey:
+		return keyIdentity{kind: keyKindTypedKey, identity: k.Identity()}
+	default:
+		return keyIdentity{kind: keyKindString, s: key.String()}
+	}
+}
@@ -113,3 +113,82 @@ func Test_entryStringKey_String(t *testing.T) {
 		})
 	}
 }
+
+// legacyKey is a key implementing only the EntryKey interface.
+type legacyKey string
+
+func (lk legacyKey) String() string {
+	return string(lk)
+}
+
+func Test_keyOf(t *testing.T) {
+	tests := []struct {
+		name string
+		key  EntryKey
+		want keyIdentity
+	}{
+		{
+			name: "String key is indexed by its identity",
+			key:  NewStringKey("1"),
+			want: keyIdentity{kind: keyKindStringKey, s: "1"},
+		},
+		{
+			name: "Int key is indexed by its identity",
+			key:  NewIntKey(1),
+			want: keyIdentity{kind: keyKindIntKey, i: 1},
+		},
+		{
+			name: "Legacy key is indexed by its string representation",
+			key:  legacyKey("1"),
+			want: keyIdentity{kind: keyKindString, s: "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keyOf(tt.key); got != tt.want {
+				t.Errorf("keyOf() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_keyOf_Collisions(t *testing.T) {
+	tests := []struct {
+		name string
+		key1 EntryKey
+		key2 EntryKey
+		want bool
+	}{
+		{
+			name: "Int key and string key with the same string representation",
+			key1: NewIntKey(1),
+			key2: NewStringKey("1"),
+			want: false,
+		},
+		{
+			name: "String key and legacy key with the same string representation",
+			key1: NewStringKey("1"),
+			key2: legacyKey("1"),
+			want: false,
+		},
+		{
+			name: "Two int keys with the same value",
+			key1: NewIntKey(1),
+			key2: NewIntKey(1),
+			want: true,
+		},
+		{
+			name: "Two legacy keys with the same string representation",
+			key1: legacyKey("1"),
+			key2: legacyKey("1"),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keyOf(tt.key1) == keyOf(tt.key2); got != tt.want {
+				t.Errorf("keyOf(%#v) == keyOf(%#v) = %t, want %t", tt.key1, tt.key2, got, tt.want)
+			}
+		})
+	}
+}
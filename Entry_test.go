@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/mmaFR/LruCache/clocktest"
 )
 
 func Test_entry_ExceedMaxAge(t *testing.T) {
@@ -416,6 +418,7 @@ func Test_entry_GetElapsedTimeFromLastAccess(t *testing.T) {
 		accessTime   time.Time
 		creationTime time.Time
 	}
+	var clock = clocktest.NewFakeClock(time.Now())
 	tests := []struct {
 		name   string
 		fields fields
@@ -428,10 +431,10 @@ func Test_entry_GetElapsedTimeFromLastAccess(t *testing.T) {
 				value:        nil,
 				ttl:          0,
 				maxAge:       0,
-				accessTime:   time.Now(),
+				accessTime:   clock.Now(),
 				creationTime: time.Time{},
 			},
-			want: time.Now().Add(time.Second * 5).Sub(time.Now()),
+			want: time.Second * 5,
 		},
 	}
 	for _, tt := range tests {
@@ -443,10 +446,11 @@ func Test_entry_GetElapsedTimeFromLastAccess(t *testing.T) {
 				maxAge:       tt.fields.maxAge,
 				accessTime:   tt.fields.accessTime,
 				creationTime: tt.fields.creationTime,
+				clock:        clock,
 			}
-			time.Sleep(time.Second * 5)
-			if got := e.GetElapsedTimeFromLastAccess(); got-tt.want > time.Duration(time.Millisecond*500) {
-				t.Errorf("Time delta = %v, want < 500ms", got-tt.want)
+			clock.Advance(time.Second * 5)
+			if got := e.GetElapsedTimeFromLastAccess(); got != tt.want {
+				t.Errorf("GetElapsedTimeFromLastAccess() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -1077,3 +1081,110 @@ func Test_entry_NewEntry(t *testing.T) {
 		})
 	}
 }
+
+func TestWithEntryClock(t *testing.T) {
+	var clock = clocktest.NewFakeClock(time.Date(2021, 12, 15, 0, 0, 0, 0, time.UTC))
+	var e = NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15), WithEntryClock(clock))
+	if got := e.GetAccessTime(); !got.Equal(clock.Now()) {
+		t.Errorf("GetAccessTime() = %v, want %v", got, clock.Now())
+	}
+
+	clock.Advance(Second(8))
+	if e.IsExpired() {
+		t.Error("IsExpired() = true before the TTL, want false")
+	}
+	if got := e.GetDelayToTTL(); got != Second(2) {
+		t.Errorf("GetDelayToTTL() = %v, want %v", got, Second(2))
+	}
+	e.UpdateAccessTime()
+	if got := e.GetAccessTime(); !got.Equal(clock.Now()) {
+		t.Errorf("GetAccessTime() = %v, want %v", got, clock.Now())
+	}
+
+	clock.Advance(Second(5))
+	if got := e.GetAge(); got != Second(13) {
+		t.Errorf("GetAge() = %v, want %v", got, Second(13))
+	}
+	if got := e.GetDurationBeforeFlush(); got != Second(2) {
+		t.Errorf("GetDurationBeforeFlush() = %v, want %v", got, Second(2))
+	}
+
+	clock.Advance(Second(3))
+	if !e.ExceedMaxAge() || e.ExceedTTL() || !e.IsExpired() {
+		t.Errorf("ExceedMaxAge() = %t, ExceedTTL() = %t, IsExpired() = %t, want true, false, true", e.ExceedMaxAge(), e.ExceedTTL(), e.IsExpired())
+	}
+}
+
+func Test_entry_NoExpiration(t *testing.T) {
+	type want struct {
+		exceedTTL           bool
+		exceedMaxAge        bool
+		delayToTTL          time.Duration
+		delayToMaxAge       time.Duration
+		durationBeforeFlush time.Duration
+	}
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		maxAge time.Duration
+		want   want
+	}{
+		{
+			name:   "Neither TTL nor max age",
+			ttl:    NoExpiration,
+			maxAge: NoExpiration,
+			want:   want{delayToTTL: NoExpiration, delayToMaxAge: NoExpiration, durationBeforeFlush: NoExpiration},
+		},
+		{
+			name:   "Zero TTL and max age",
+			ttl:    0,
+			maxAge: 0,
+			want:   want{delayToTTL: NoExpiration, delayToMaxAge: NoExpiration, durationBeforeFlush: NoExpiration},
+		},
+		{
+			name:   "Negative TTL and max age",
+			ttl:    -Second(10),
+			maxAge: -Second(10),
+			want:   want{delayToTTL: NoExpiration, delayToMaxAge: NoExpiration, durationBeforeFlush: NoExpiration},
+		},
+		{
+			name:   "Max age only",
+			ttl:    NoExpiration,
+			maxAge: Second(300),
+			want:   want{exceedMaxAge: true, delayToTTL: NoExpiration, delayToMaxAge: 0, durationBeforeFlush: 0},
+		},
+		{
+			name:   "TTL only",
+			ttl:    Second(300),
+			maxAge: 0,
+			want:   want{delayToTTL: Second(100), delayToMaxAge: NoExpiration, durationBeforeFlush: Second(100)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var clock = clocktest.NewFakeClock(time.Now())
+			var e = NewEntry(NewStringKey("A"), "A entry", tt.ttl, tt.maxAge, WithEntryClock(clock))
+			clock.Advance(Second(400))
+			e.UpdateAccessTime()
+			clock.Advance(Second(200))
+			if got := e.ExceedTTL(); got != tt.want.exceedTTL {
+				t.Errorf("ExceedTTL() = %t, want %t", got, tt.want.exceedTTL)
+			}
+			if got := e.ExceedMaxAge(); got != tt.want.exceedMaxAge {
+				t.Errorf("ExceedMaxAge() = %t, want %t", got, tt.want.exceedMaxAge)
+			}
+			if got, want := e.IsExpired(), tt.want.exceedTTL || tt.want.exceedMaxAge; got != want {
+				t.Errorf("IsExpired() = %t, want %t", got, want)
+			}
+			if got := e.GetDelayToTTL(); got != tt.want.delayToTTL {
+				t.Errorf("GetDelayToTTL() = %v, want %v", got, tt.want.delayToTTL)
+			}
+			if got := e.GetDelayToMaxAge(); got != tt.want.delayToMaxAge {
+				t.Errorf("GetDelayToMaxAge() = %v, want %v", got, tt.want.delayToMaxAge)
+			}
+			if got := e.GetDurationBeforeFlush(); got != tt.want.durationBeforeFlush {
+				t.Errorf("GetDurationBeforeFlush() = %v, want %v", got, tt.want.durationBeforeFlush)
+			}
+		})
+	}
+}
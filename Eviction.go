@@ -0,0 +1,59 @@
+package LruCache
+
+// EvictionReason describes why an entry left the cache.
+type EvictionReason uint8
+
+const (
+	// EvictionCapacity means the entry was the one chosen by the eviction policy and the cache was full.
+	EvictionCapacity EvictionReason = iota
+	// EvictionTTL means the entry exceeded its TTL.
+	EvictionTTL
+	// EvictionMaxAge means the entry exceeded its max age.
+	EvictionMaxAge
+	// EvictionRemove means the entry was explicitly removed with Remove or RemoveLruEntry.
+	EvictionRemove
+	// EvictionFlush means the cache was flushed.
+	EvictionFlush
+	// EvictionResize means the entry was removed while downsizing the cache.
+	EvictionResize
+	// EvictionReplace means the entry was replaced by a new entry having the same key.
+	EvictionReplace
+)
+
+// String returns the eviction reason string representation.
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionCapacity:
+		return "capacity"
+	case EvictionTTL:
+		return "ttl"
+	case EvictionMaxAge:
+		return "max_age"
+	case EvictionRemove:
+		return "remove"
+	case EvictionFlush:
+		return "flush"
+	case EvictionResize:
+		return "resize"
+	case EvictionReplace:
+		return "replace"
+	default:
+		return "unknown"
+	}
+}
+
+// expiryReason returns the eviction reason of an expired entry.
+func expiryReason(cacheEntry Entry) EvictionReason {
+	if cacheEntry.ExceedTTL() {
+		return EvictionTTL
+	}
+	return EvictionMaxAge
+}
+
+// OnEvict sets the callback called each time an entry leaves the cache, with the reason why.
+// The callback is called synchronously, it is the place to release the resources held by the entry value.
+func OnEvict(callback func(Entry, EvictionReason)) CacheOption {
+	return func(s *cacheSettings) {
+		s.onEvict = callback
+	}
+}
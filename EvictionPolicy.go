@@ -0,0 +1,174 @@
+package LruCache
+
+import (
+	"container/list"
+	"math/rand"
+	"time"
+)
+
+// concurrentAccessPolicy is implemented by the eviction policies whose OnAccess method may run concurrently
+// with itself while no other method runs, so that a syncCache records the hits under its read lock.
+type concurrentAccessPolicy interface {
+	EvictionPolicy
+	concurrentAccess()
+}
+
+// WithEvictionPolicy sets the constructor of the eviction policy of the cache, called with the cache capacity.
+// The sharded caches call it once per shard, with the shard capacity.
+func WithEvictionPolicy(newPolicy func(capacity uint32) EvictionPolicy) CacheOption {
+	return func(s *cacheSettings) {
+		s.newPolicy = newPolicy
+	}
+}
+
+// lruPolicy evicts the least recently used entry, the entries are linked in the list with SetLruLink.
+type lruPolicy struct {
+	list *list.List
+}
+
+// OnInsert pushes the entry to the front of the list.
+func (p *lruPolicy) OnInsert(cacheEntry Entry) {
+	cacheEntry.SetLruLink(p.list.PushFront(cacheEntry))
+}
+
+// OnAccess moves the entry to the front of the list.
+func (p *lruPolicy) OnAccess(cacheEntry Entry) {
+	p.list.MoveToFront(cacheEntry.GetLruLink())
+}
+
+// OnRemove unlinks the entry from the list.
+func (p *lruPolicy) OnRemove(cacheEntry Entry, _ EvictionReason) {
+	p.list.Remove(cacheEntry.GetLruLink())
+}
+
+// Victim returns the entry at the back of the list.
+func (p *lruPolicy) Victim(_ Entry) Entry {
+	if p.list.Len() == 0 {
+		return nil
+	} else {
+		return p.list.Back().Value.(Entry)
+	}
+}
+
+// Reset replaces the list by an empty one.
+func (p *lruPolicy) Reset() {
+	p.list = list.New()
+}
+
+// newLruPolicy returns the default eviction policy.
+func newLruPolicy(_ uint32) EvictionPolicy {
+	return &lruPolicy{list: list.New()}
+}
+
+// WithLruEviction makes the cache evict the least recently used entry, it is the default policy.
+func WithLruEviction() CacheOption {
+	return WithEvictionPolicy(newLruPolicy)
+}
+
+// fifoPolicy evicts the oldest entry, the hits don't change the order of the entries.
+type fifoPolicy struct {
+	lruPolicy
+}
+
+// OnAccess does nothing, the entries keep their insertion order.
+func (p *fifoPolicy) OnAccess(_ Entry) {}
+
+// newFifoPolicy returns a new FIFO eviction policy.
+func newFifoPolicy(_ uint32) EvictionPolicy {
+	return &fifoPolicy{lruPolicy{list: list.New()}}
+}
+
+// WithFifoEviction makes the cache evict the oldest entry, whatever its hits. Replacing an entry makes it the newest one.
+func WithFifoEviction() CacheOption {
+	return WithEvictionPolicy(newFifoPolicy)
+}
+
+// mruPolicy evicts the most recently used entry.
+type mruPolicy struct {
+	lruPolicy
+}
+
+// Victim returns the entry at the front of the list.
+func (p *mruPolicy) Victim(_ Entry) Entry {
+	if p.list.Len() == 0 {
+		return nil
+	} else {
+		return p.list.Front().Value.(Entry)
+	}
+}
+
+// newMruPolicy returns a new MRU eviction policy.
+func newMruPolicy(_ uint32) EvictionPolicy {
+	return &mruPolicy{lruPolicy{list: list.New()}}
+}
+
+// WithMruEviction makes the cache evict the most recently used entry, which suits the cyclic accesses
+// to more entries than the capacity.
+func WithMruEviction() CacheOption {
+	return WithEvictionPolicy(newMruPolicy)
+}
+
+// randomPolicy evicts an entry picked at random. The picked entry stays the victim until it is removed,
+// so that GetLruEntry and RemoveLruEntry agree.
+type randomPolicy struct {
+	entries []Entry
+	index   map[keyIdentity]int
+	victim  Entry
+	rand    *rand.Rand
+}
+
+// OnInsert appends the entry to the slice.
+func (p *randomPolicy) OnInsert(cacheEntry Entry) {
+	p.index[keyOf(cacheEntry.Key())] = len(p.entries)
+	p.entries = append(p.entries, cacheEntry)
+}
+
+// OnAccess does nothing, the hits don't change the victim.
+func (p *randomPolicy) OnAccess(_ Entry) {}
+
+// OnRemove swaps the entry with the last one of the slice and truncates it.
+func (p *randomPolicy) OnRemove(cacheEntry Entry, _ EvictionReason) {
+	var key = keyOf(cacheEntry.Key())
+	var i, exists = p.index[key]
+	if !exists {
+		return
+	}
+	var last = len(p.entries) - 1
+	p.entries[i] = p.entries[last]
+	p.index[keyOf(p.entries[i].Key())] = i
+	p.entries[last] = nil
+	p.entries = p.entries[:last]
+	delete(p.index, key)
+	if p.victim == cacheEntry {
+		p.victim = nil
+	}
+}
+
+// Victim returns the picked entry, picking one at random if needed.
+func (p *randomPolicy) Victim(_ Entry) Entry {
+	if p.victim == nil && len(p.entries) > 0 {
+		p.victim = p.entries[p.rand.Intn(len(p.entries))]
+	}
+	return p.victim
+}
+
+// Reset forgets all the entries.
+func (p *randomPolicy) Reset() {
+	p.entries = make([]Entry, 0, cap(p.entries))
+	p.index = make(map[keyIdentity]int, cap(p.entries))
+	p.victim = nil
+}
+
+// newRandomPolicy returns a new random eviction policy.
+func newRandomPolicy(capacity uint32) EvictionPolicy {
+	return &randomPolicy{
+		entries: make([]Entry, 0, capacity),
+		index:   make(map[keyIdentity]int, capacity),
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// WithRandomEviction makes the cache evict an entry picked at random, which needs no bookkeeping on the hits.
+func WithRandomEviction() CacheOption {
+	return WithEvictionPolicy(newRandomPolicy)
+}
@@ -0,0 +1,554 @@
+package LruCache
+
+import (
+	"container/list"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmaFR/LruCache/clocktest"
+)
+
+// evictionPolicies are the eviction policies checked against the cache contract.
+// The scan resistant policies are expected to beat LRU on the scan polluted traces.
+var evictionPolicies = []struct {
+	name          string
+	option        CacheOption
+	scanResistant bool
+}{
+	{name: "LRU", option: WithLruEviction()},
+	{name: "LFU", option: WithLfuEviction(), scanResistant: true},
+	{name: "ARC", option: WithArcEviction(), scanResistant: true},
+	{name: "W-TinyLFU", option: WithTinyLfuEviction(), scanResistant: true},
+	{name: "SLRU", option: WithSlruEviction(DefaultSlruProtectedRatio), scanResistant: true},
+	{name: "2Q", option: WithTwoQueueEviction(), scanResistant: true},
+	{name: "CLOCK", option: WithClockEviction()},
+	{name: "SIEVE", option: WithSieveEviction(), scanResistant: true},
+	{name: "LIRS", option: WithLirsEviction(), scanResistant: true},
+	{name: "FIFO", option: WithFifoEviction()},
+	{name: "MRU", option: WithMruEviction()},
+	{name: "Random", option: WithRandomEviction()},
+}
+
+func intEntry(key int) Entry {
+	return NewEntry(NewIntKey(key), key, NoExpiration, NoExpiration)
+}
+
+// replayTrace looks up each key of the trace, adds the missing ones, and returns the cache hit ratio.
+func replayTrace(c Cache, trace []int) float64 {
+	for _, key := range trace {
+		if c.Get(NewIntKey(key)) == nil {
+			c.Add(intEntry(key))
+		}
+	}
+	return c.Stats().HitRatio()
+}
+
+// scanTrace returns rounds of lookups of a hot set of hot keys looked up twice, each followed by a scan of scan never seen keys.
+func scanTrace(rounds int, hot int, scan int) []int {
+	var trace = make([]int, 0, rounds*(2*hot+scan))
+	var next = hot
+	for r := 0; r < rounds; r++ {
+		for k := 0; k < 2*hot; k++ {
+			trace = append(trace, k%hot)
+		}
+		for k := 0; k < scan; k++ {
+			trace = append(trace, next)
+			next++
+		}
+	}
+	return trace
+}
+
+// pollutedTrace returns length lookups of keys following a Zipf distribution over a few hundred keys,
+// polluted every thousand lookups by a scan of never seen keys.
+func pollutedTrace(seed int64, length int) []int {
+	var r = rand.New(rand.NewSource(seed))
+	var zipf = rand.NewZipf(r, 1.1, 1, 511)
+	var trace = make([]int, 0, length)
+	var next = 1 << 20
+	for len(trace) < length {
+		if len(trace)%1000 == 999 {
+			for k := 0; k < 300; k++ {
+				trace = append(trace, next)
+				next++
+			}
+		}
+		trace = append(trace, int(zipf.Uint64()))
+	}
+	return trace[:length]
+}
+
+// benchmarkTrace replays a Zipf trace polluted by scans on a cache of 1024 entries, and reports its hit ratio.
+func benchmarkTrace(b *testing.B, newCache func(size uint32) Cache) {
+	var trace = pollutedTrace(1, 1<<16)
+	var c = newCache(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var key = trace[i%len(trace)]
+		if c.Get(NewIntKey(key)) == nil {
+			c.Add(intEntry(key))
+		}
+	}
+	b.ReportMetric(c.Stats().HitRatio(), "hit-ratio")
+}
+
+// listKeys returns the int keys of the entries or ghosts of the list, from the front.
+func listKeys(l *list.List) []int {
+	var keys = make([]int, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		switch v := e.Value.(type) {
+		case Entry:
+			keys = append(keys, v.Value().(int))
+		default:
+			keys = append(keys, v.(keyIdentity).i)
+		}
+	}
+	return keys
+}
+
+// drainVictims removes the entries one by one with RemoveLruEntry and returns the number of removed entries.
+func drainVictims(t *testing.T, c Cache) uint32 {
+	t.Helper()
+	var removed uint32
+	for c.Len() > 0 {
+		var victim = c.GetLruEntry()
+		if victim == nil {
+			t.Fatalf("GetLruEntry() = nil with %d entries in the cache", c.Len())
+		}
+		if got := c.RemoveLruEntry(); got != victim {
+			t.Fatalf("RemoveLruEntry() = %v, want the entry returned by GetLruEntry() %v", got, victim)
+		}
+		if c.Contains(victim.Key()) {
+			t.Fatalf("The removed entry %v is still in the cache", victim.Key())
+		}
+		removed++
+	}
+	if got := c.GetLruEntry(); got != nil {
+		t.Errorf("GetLruEntry() = %v on an empty cache, want nil", got)
+	}
+	return removed
+}
+
+func TestEvictionPolicies_Contract(t *testing.T) {
+	for _, policy := range evictionPolicies {
+		t.Run(policy.name, func(t *testing.T) {
+			t.Run("Capacity eviction", func(t *testing.T) {
+				var evicted int
+				var c = NewCache(8, policy.option, OnEvict(func(e Entry, reason EvictionReason) {
+					if reason == EvictionCapacity {
+						evicted++
+					}
+				}))
+				for i := 0; i < 20; i++ {
+					var result, displaced = c.Add(intEntry(i))
+					if i < 8 && (result != EntryInserted || displaced != nil) {
+						t.Errorf("Add(%d) = %s, %v, want %s, nil", i, result, displaced, EntryInserted)
+					}
+					if i >= 8 {
+						if result != LruEntryEvicted || displaced == nil {
+							t.Fatalf("Add(%d) = %s, %v, want %s and the evicted entry", i, result, displaced, LruEntryEvicted)
+						}
+						if c.Contains(displaced.Key()) {
+							t.Errorf("The evicted entry %v is still in the cache", displaced.Key())
+						}
+					}
+					if i%3 == 0 {
+						c.Get(NewIntKey(i))
+					}
+				}
+				if l := c.Len(); l != 8 {
+					t.Errorf("Len() = %d, want 8", l)
+				}
+				if evicted != 12 {
+					t.Errorf("%d evictions notified, want 12", evicted)
+				}
+				if removed := drainVictims(t, c); removed != 8 {
+					t.Errorf("%d entries drained, want 8", removed)
+				}
+			})
+			t.Run("Replace and remove", func(t *testing.T) {
+				var c = NewCache(4, policy.option)
+				for i := 0; i < 4; i++ {
+					c.Add(intEntry(i))
+				}
+				if result, displaced := c.Add(intEntry(2)); result != EntryReplaced || displaced == nil {
+					t.Errorf("Add() = %s, %v, want %s and the replaced entry", result, displaced, EntryReplaced)
+				}
+				if ok, _ := c.Remove(NewIntKey(1)); !ok {
+					t.Error("Remove() = false, want true")
+				}
+				if removed := drainVictims(t, c); removed != 3 {
+					t.Errorf("%d entries drained, want 3", removed)
+				}
+			})
+			t.Run("Resize", func(t *testing.T) {
+				var c = NewCache(8, policy.option)
+				for i := 0; i < 8; i++ {
+					c.Add(intEntry(i))
+					c.Get(NewIntKey(i % 3))
+				}
+				if n, flushed := c.Resize(3); n != 5 || len(flushed) != 5 {
+					t.Errorf("Resize() = %d, %d entries, want 5, 5 entries", n, len(flushed))
+				}
+				if l := c.Len(); l != 3 {
+					t.Errorf("Len() = %d, want 3", l)
+				}
+				c.Resize(6)
+				for i := 10; i < 13; i++ {
+					if result, _ := c.Add(intEntry(i)); result != EntryInserted {
+						t.Errorf("Add(%d) = %s after growing the cache, want %s", i, result, EntryInserted)
+					}
+				}
+				if result, _ := c.Add(intEntry(13)); result != LruEntryEvicted {
+					t.Errorf("Add() = %s in a full cache, want %s", result, LruEntryEvicted)
+				}
+				if removed := drainVictims(t, c); removed != 6 {
+					t.Errorf("%d entries drained, want 6", removed)
+				}
+			})
+			t.Run("Expiry", func(t *testing.T) {
+				var clock = clocktest.NewFakeClock(time.Now())
+				var c = NewCache(4, policy.option, WithClock(clock))
+				for i := 0; i < 4; i++ {
+					c.Add(NewEntry(NewIntKey(i), i, Second(10*(i+1)), NoExpiration, WithEntryClock(clock)))
+				}
+				clock.Advance(Second(15))
+				if got := c.Get(NewIntKey(0)); got != nil {
+					t.Errorf("Get() = %v for an expired entry, want nil", got)
+				}
+				clock.Advance(Second(10))
+				if n, _ := c.HouseCleaning(); n != 1 {
+					t.Errorf("HouseCleaning() = %d, want 1", n)
+				}
+				if removed := drainVictims(t, c); removed != 2 {
+					t.Errorf("%d entries drained, want 2", removed)
+				}
+			})
+			t.Run("Flush", func(t *testing.T) {
+				var c = NewCache(4, policy.option)
+				for i := 0; i < 6; i++ {
+					c.Add(intEntry(i))
+					c.Get(NewIntKey(i))
+				}
+				c.Flush()
+				if got := c.GetLruEntry(); got != nil {
+					t.Errorf("GetLruEntry() = %v after Flush(), want nil", got)
+				}
+				for i := 0; i < 4; i++ {
+					if result, _ := c.Add(intEntry(i)); result != EntryInserted {
+						t.Errorf("Add(%d) = %s after Flush(), want %s", i, result, EntryInserted)
+					}
+				}
+				if removed := drainVictims(t, c); removed != 4 {
+					t.Errorf("%d entries drained, want 4", removed)
+				}
+			})
+		})
+	}
+}
+
+func TestEvictionPolicies_RandomOperations(t *testing.T) {
+	for _, policy := range evictionPolicies {
+		t.Run(policy.name, func(t *testing.T) {
+			var rnd = rand.New(rand.NewSource(42))
+			var clock = clocktest.NewFakeClock(time.Now())
+			var c = NewCache(8, policy.option, WithClock(clock))
+			for i := 0; i < 20000; i++ {
+				var key = rnd.Intn(32)
+				switch op := rnd.Intn(100); {
+				case op < 40:
+					c.Add(NewEntry(NewIntKey(key), key, Second(rnd.Intn(60)), NoExpiration, WithEntryClock(clock)))
+				case op < 85:
+					c.Get(NewIntKey(key))
+				case op < 90:
+					c.Remove(NewIntKey(key))
+				case op < 93:
+					if victim := c.GetLruEntry(); victim != nil && !c.Contains(victim.Key()) {
+						t.Fatalf("GetLruEntry() = %v which is not in the cache", victim.Key())
+					}
+					c.RemoveLruEntry()
+				case op < 95:
+					c.Resize(uint32(4 + rnd.Intn(12)))
+				case op < 99:
+					clock.Advance(Second(1))
+				default:
+					c.HouseCleaning()
+				}
+				if c.Len() > c.Capacity() {
+					t.Fatalf("Len() = %d greater than Capacity() = %d", c.Len(), c.Capacity())
+				}
+			}
+			var l = c.Len()
+			if removed := drainVictims(t, c); removed != l {
+				t.Errorf("%d entries drained, want %d", removed, l)
+			}
+		})
+	}
+}
+
+func TestEvictionPolicies_HitRatio(t *testing.T) {
+	traces := []struct {
+		name     string
+		capacity uint32
+		trace    []int
+	}{
+		{name: "Hot set and scans", capacity: 8, trace: scanTrace(50, 4, 6)},
+		{name: "Zipf polluted by scans", capacity: 64, trace: pollutedTrace(1, 50000)},
+	}
+	for _, tr := range traces {
+		t.Run(tr.name, func(t *testing.T) {
+			var lru = replayTrace(NewCache(tr.capacity), tr.trace)
+			for _, policy := range evictionPolicies {
+				var got = replayTrace(NewCache(tr.capacity, policy.option), tr.trace)
+				t.Logf("%s hit ratio = %.3f", policy.name, got)
+				if policy.scanResistant && got <= lru {
+					t.Errorf("%s hit ratio = %.3f, want more than the LRU hit ratio %.3f", policy.name, got, lru)
+				}
+			}
+		})
+	}
+}
+
+func TestEvictionPolicies_SyncCache(t *testing.T) {
+	for _, policy := range evictionPolicies {
+		t.Run(policy.name, func(t *testing.T) {
+			var c = NewSyncCache(64, policy.option)
+			for k := 0; k < 64; k++ {
+				c.Add(intEntry(k))
+			}
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 1000; i++ {
+						var k = (g*1000 + i) % 128
+						if c.Get(NewIntKey(k)) == nil {
+							c.Add(intEntry(k))
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			var s = c.Stats()
+			if s.Hits+s.Misses != 8000 {
+				t.Errorf("%d hits and %d misses, want 8000 lookups", s.Hits, s.Misses)
+			}
+			if l := c.Len(); l != 64 {
+				t.Errorf("Len() = %d, want 64", l)
+			}
+		})
+	}
+}
+
+// recordingPolicy is a custom eviction policy evicting the entry having the lowest int value, recording its calls.
+type recordingPolicy struct {
+	entries map[int]Entry
+	calls   []string
+}
+
+func (p *recordingPolicy) OnInsert(entry Entry) {
+	p.entries[entry.Value().(int)] = entry
+	p.calls = append(p.calls, fmt.Sprintf("insert %v", entry.Value()))
+}
+
+func (p *recordingPolicy) OnAccess(entry Entry) {
+	p.calls = append(p.calls, fmt.Sprintf("access %v", entry.Value()))
+}
+
+func (p *recordingPolicy) OnRemove(entry Entry, reason EvictionReason) {
+	delete(p.entries, entry.Value().(int))
+	p.calls = append(p.calls, fmt.Sprintf("remove %v %s", entry.Value(), reason))
+}
+
+func (p *recordingPolicy) Victim(_ Entry) Entry {
+	var victim Entry
+	for value, entry := range p.entries {
+		if victim == nil || value < victim.Value().(int) {
+			victim = entry
+		}
+	}
+	return victim
+}
+
+func TestWithEvictionPolicy(t *testing.T) {
+	var policies []*recordingPolicy
+	var newPolicy = func(capacity uint32) EvictionPolicy {
+		var np = &recordingPolicy{entries: make(map[int]Entry, capacity)}
+		policies = append(policies, np)
+		return np
+	}
+	var c = NewCache(2, WithEvictionPolicy(newPolicy))
+	c.Add(intEntry(5))
+	c.Add(intEntry(3))
+	c.Get(NewIntKey(3))
+	if _, displaced := c.Add(intEntry(7)); displaced == nil || displaced.Value() != 3 {
+		t.Errorf("Add() evicted %v, want the entry 3", displaced)
+	}
+	c.Resize(4)
+	c.Flush()
+	var want = []string{
+		"insert 5", "insert 3", "access 3", fmt.Sprintf("remove 3 %s", EvictionCapacity), "insert 7",
+		fmt.Sprintf("remove 5 %s", EvictionFlush), fmt.Sprintf("remove 7 %s", EvictionFlush),
+	}
+	var got = policies[0].calls
+	sort.Strings(got[5:])
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Policy calls = %v, want %v", got, want)
+	}
+	if len(policies[0].entries) != 0 {
+		t.Errorf("The policy still tracks %d entries after Flush()", len(policies[0].entries))
+	}
+
+	policies = nil
+	NewShardedCache(4, 8, WithEvictionPolicy(newPolicy))
+	if len(policies) != 4 {
+		t.Errorf("%d policies created for 4 shards, want 4", len(policies))
+	}
+}
+
+func TestBasicPolicies_Order(t *testing.T) {
+	tests := []struct {
+		name   string
+		option CacheOption
+		want   []int
+	}{
+		{name: "LRU", option: WithLruEviction(), want: []int{1, 3, 0, 2}},
+		{name: "FIFO", option: WithFifoEviction(), want: []int{0, 1, 2, 3}},
+		{name: "MRU", option: WithMruEviction(), want: []int{2, 0, 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = NewCache(4, tt.option)
+			for i := 0; i < 4; i++ {
+				c.Add(intEntry(i))
+			}
+			c.Get(NewIntKey(0))
+			c.Get(NewIntKey(2))
+			var got = make([]int, 0, 4)
+			for c.Len() > 0 {
+				got = append(got, c.RemoveLruEntry().Value().(int))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eviction order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRandomPolicy(t *testing.T) {
+	var p = newRandomPolicy(64).(*randomPolicy)
+	p.rand = rand.New(rand.NewSource(1))
+	var c = NewCache(64, WithEvictionPolicy(func(_ uint32) EvictionPolicy { return p }))
+	for i := 0; i < 64; i++ {
+		c.Add(intEntry(i))
+	}
+	var evicted = make(map[int]bool)
+	for i := 64; i < 128; i++ {
+		var _, displaced = c.Add(intEntry(i))
+		evicted[displaced.Value().(int)] = true
+	}
+	var old int
+	for k := range evicted {
+		if k < 64 {
+			old++
+		}
+	}
+	if old == 0 || old == 64 {
+		t.Errorf("%d of the 64 evicted entries were the first ones, want a mix of old and new entries", old)
+	}
+	if len(p.entries) != 64 || len(p.index) != 64 {
+		t.Errorf("The policy tracks %d entries and %d keys, want 64", len(p.entries), len(p.index))
+	}
+	for key, i := range p.index {
+		if keyOf(p.entries[i].Key()) != key {
+			t.Fatalf("The index of %v points to %v", key, p.entries[i].Key())
+		}
+	}
+}
+
+// pinningPolicy is a custom eviction policy which never returns a victim, recording the capacity changes.
+type pinningPolicy struct {
+	capacities []uint32
+}
+
+func (p *pinningPolicy) OnInsert(_ Entry) {}
+
+func (p *pinningPolicy) OnAccess(_ Entry) {}
+
+func (p *pinningPolicy) OnRemove(_ Entry, _ EvictionReason) {}
+
+func (p *pinningPolicy) Victim(_ Entry) Entry {
+	return nil
+}
+
+func (p *pinningPolicy) Resize(capacity uint32) {
+	p.capacities = append(p.capacities, capacity)
+}
+
+func TestWithEvictionPolicy_NoVictim(t *testing.T) {
+	var p = new(pinningPolicy)
+	var c = NewCache(2, WithEvictionPolicy(func(_ uint32) EvictionPolicy { return p }))
+	for i := 0; i < 5; i++ {
+		var result, displaced = c.Add(intEntry(i))
+		if i < 2 && result != EntryInserted {
+			t.Errorf("Add(%d) = %s, want %s", i, result, EntryInserted)
+		}
+		if i >= 2 && (result != EntryRejected || displaced != nil) {
+			t.Errorf("Add(%d) = %s, %v in a full cache without victim, want %s, nil", i, result, displaced, EntryRejected)
+		}
+	}
+	if c.Len() != 2 || c.Contains(NewIntKey(4)) {
+		t.Errorf("Len() = %d, want the 2 first entries only", c.Len())
+	}
+	if result, _ := c.Add(intEntry(1)); result != EntryReplaced {
+		t.Errorf("Add() = %s for an existing key, want %s", result, EntryReplaced)
+	}
+	if n, flushed := c.Resize(1); n != 0 || len(flushed) != 0 {
+		t.Errorf("Resize() = %d, %v without victim, want 0, []", n, flushed)
+	}
+	if !c.IsFull() {
+		t.Errorf("IsFull() = false with %d entries and a capacity of %d, want true", c.Len(), c.Capacity())
+	}
+	if result, _ := c.Add(intEntry(5)); result != EntryRejected {
+		t.Errorf("Add() = %s in a cache over its capacity, want %s", result, EntryRejected)
+	}
+	if s := c.Stats(); s.Inserts != 2 || s.ResizeEvictions != 0 {
+		t.Errorf("Stats() = %+v, want 2 inserts and no resize eviction", s)
+	}
+	if !reflect.DeepEqual(p.capacities, []uint32{1}) {
+		t.Errorf("Resize() notified the capacities %v to the policy, want [1]", p.capacities)
+	}
+}
+
+func TestNewPolicyCaches_OptionsSlice(t *testing.T) {
+	tests := []struct {
+		name        string
+		constructor func(uint32, ...CacheOption) Cache
+	}{
+		{name: "LFU", constructor: NewLfuCache},
+		{name: "ARC", constructor: NewArcCache},
+		{name: "W-TinyLFU", constructor: NewTinyLfuCache},
+		{name: "SLRU", constructor: NewSlruCache},
+		{name: "2Q", constructor: NewTwoQueueCache},
+		{name: "CLOCK", constructor: NewClockCache},
+		{name: "SIEVE", constructor: NewSieveCache},
+		{name: "LIRS", constructor: NewLirsCache},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options = make([]CacheOption, 1, 2)
+			options[0] = WithClock(SystemClock)
+			tt.constructor(4, options...)
+			if spare := options[:2][1]; spare != nil {
+				t.Errorf("%s cache constructor wrote its eviction option in the spare capacity of the options slice", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package LruCache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmaFR/LruCache/clocktest"
+)
+
+type evictionRecord struct {
+	key    string
+	reason EvictionReason
+}
+
+func TestOnEvict(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity uint32
+		scenario func(c Cache, clock *clocktest.FakeClock)
+		want     []evictionRecord
+	}{
+		{
+			name:     "Capacity eviction",
+			capacity: 1,
+			scenario: func(c Cache, clock *clocktest.FakeClock) {
+				c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)))
+				c.Add(NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)))
+			},
+			want: []evictionRecord{{key: "A", reason: EvictionCapacity}},
+		},
+		{
+			name:     "TTL expiry on read",
+			capacity: 4,
+			scenario: func(c Cache, clock *clocktest.FakeClock) {
+				c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15), WithEntryClock(clock)))
+				clock.Advance(Second(11))
+				c.Get(NewStringKey("A"))
+			},
+			want: []evictionRecord{{key: "A", reason: EvictionTTL}},
+		},
+		{
+			name:     "Max age expiry on house cleaning",
+			capacity: 4,
+			scenario: func(c Cache, clock *clocktest.FakeClock) {
+				c.Add(NewEntry(NewStringKey("A"), "A entry", Second(20), Second(5), WithEntryClock(clock)))
+				c.Add(NewEntry(NewStringKey("B"), "B entry", Second(20), Second(15), WithEntryClock(clock)))
+				clock.Advance(Second(6))
+				c.HouseCleaning()
+			},
+			want: []evictionRecord{{key: "A", reason: EvictionMaxAge}},
+		},
+		{
+			name:     "Explicit removal",
+			capacity: 4,
+			scenario: func(c Cache, clock *clocktest.FakeClock) {
+				c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)))
+				c.Add(NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)))
+				c.Remove(NewStringKey("B"))
+				c.Remove(NewStringKey("C"))
+				c.RemoveLruEntry()
+			},
+			want: []evictionRecord{{key: "B", reason: EvictionRemove}, {key: "A", reason: EvictionRemove}},
+		},
+		{
+			name:     "Flush",
+			capacity: 4,
+			scenario: func(c Cache, clock *clocktest.FakeClock) {
+				c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)))
+				c.Flush()
+			},
+			want: []evictionRecord{{key: "A", reason: EvictionFlush}},
+		},
+		{
+			name:     "Resize downsizing",
+			capacity: 4,
+			scenario: func(c Cache, clock *clocktest.FakeClock) {
+				c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)))
+				c.Add(NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)))
+				c.Add(NewEntry(NewStringKey("C"), "C entry", Second(10), Second(15)))
+				c.Resize(1)
+			},
+			want: []evictionRecord{{key: "A", reason: EvictionResize}, {key: "B", reason: EvictionResize}},
+		},
+		{
+			name:     "Replacement",
+			capacity: 4,
+			scenario: func(c Cache, clock *clocktest.FakeClock) {
+				c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)))
+				c.Add(NewEntry(NewStringKey("A"), "A entry, new version", Second(10), Second(15)))
+			},
+			want: []evictionRecord{{key: "A", reason: EvictionReplace}},
+		},
+		{
+			name:     "No eviction",
+			capacity: 4,
+			scenario: func(c Cache, clock *clocktest.FakeClock) {
+				c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)))
+				c.Get(NewStringKey("A"))
+				c.HouseCleaning()
+				c.Resize(8)
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []evictionRecord
+			var clock = clocktest.NewFakeClock(time.Now())
+			var c = NewCache(tt.capacity, WithClock(clock), OnEvict(func(e Entry, reason EvictionReason) {
+				got = append(got, evictionRecord{key: e.Key().String(), reason: reason})
+			}))
+			tt.scenario(c, clock)
+			if len(got) != len(tt.want) {
+				t.Fatalf("OnEvict() called with %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("OnEvict() call %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOnEvict_SameEntryAddedTwice(t *testing.T) {
+	var got []evictionRecord
+	var c = NewCache(2, OnEvict(func(e Entry, reason EvictionReason) {
+		got = append(got, evictionRecord{key: e.Key().String(), reason: reason})
+	}))
+	var a = NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15))
+	c.Add(a)
+	c.Add(NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)))
+	if result, displaced := c.Add(a); result != EntryReplaced || displaced != nil {
+		t.Errorf("Add() = %s, %v for an entry already in the cache, want %s, nil", result, displaced, EntryReplaced)
+	}
+	if len(got) != 0 {
+		t.Errorf("OnEvict() called with %v, want no call", got)
+	}
+	if c.GetWithoutAccessUpdate(NewStringKey("A")) != a || c.Len() != 2 {
+		t.Errorf("The entry added twice is no longer in the cache")
+	}
+	// The entry added twice is the most recently used one
+	if lru := c.GetLruEntry(); lru == nil || lru.Key().String() != "B" {
+		t.Errorf("GetLruEntry() = %v, want B", lru)
+	}
+}
+
+func TestOnEvict_SyncCache(t *testing.T) {
+	var got []evictionRecord
+	var c = NewSyncCache(1, OnEvict(func(e Entry, reason EvictionReason) {
+		got = append(got, evictionRecord{key: e.Key().String(), reason: reason})
+	}))
+	c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15)))
+	c.Add(NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)))
+	if len(got) != 1 || got[0] != (evictionRecord{key: "A", reason: EvictionCapacity}) {
+		t.Errorf("OnEvict() called with %v, want [{A capacity}]", got)
+	}
+}
+
+func TestEvictionReason_String(t *testing.T) {
+	tests := []struct {
+		reason EvictionReason
+		want   string
+	}{
+		{reason: EvictionCapacity, want: "capacity"},
+		{reason: EvictionTTL, want: "ttl"},
+		{reason: EvictionMaxAge, want: "max_age"},
+		{reason: EvictionRemove, want: "remove"},
+		{reason: EvictionFlush, want: "flush"},
+		{reason: EvictionResize, want: "resize"},
+		{reason: EvictionReplace, want: "replace"},
+		{reason: EvictionReason(255), want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.reason.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
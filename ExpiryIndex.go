@@ -0,0 +1,102 @@
+package LruCache
+
+import (
+	"container/heap"
+	"time"
+)
+
+// expiryItem is an entry of the expiry index.
+type expiryItem struct {
+	cacheEntry Entry
+	deadline   time.Time
+	index      int
+}
+
+// expiryHeap is a min-heap of expiry items ordered by deadline, it implements heap.Interface.
+type expiryHeap []*expiryItem
+
+func (h expiryHeap) Len() int {
+	return len(h)
+}
+
+func (h expiryHeap) Less(i, j int) bool {
+	return h[i].deadline.Before(h[j].deadline)
+}
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	var item = x.(*expiryItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	var old = *h
+	var item = old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+// expiryIndex orders the cache entries by their next expiry deadline, so that a sweep only
+// inspects the entries whose deadline is reached.
+// A deadline only moves backward when the entry TTL or max age is updated, the cache is then
+// notified through the entry lifetime hook. An access only moves the deadline forward, so the
+// index doesn't need to be updated: the entry is indexed again when its stale deadline is reached.
+type expiryIndex struct {
+	items map[keyIdentity]*expiryItem
+	heap  expiryHeap
+	clock Clock
+}
+
+// add indexes the cache entry, or updates its deadline if it is already indexed.
+// An entry which doesn't expire is not indexed.
+func (x *expiryIndex) add(cacheEntry Entry) {
+	var durationBeforeFlush = cacheEntry.GetDurationBeforeFlush()
+	if durationBeforeFlush == NoExpiration {
+		x.remove(cacheEntry)
+		return
+	}
+	var id = keyOf(cacheEntry.Key())
+	var deadline = x.clock.Now().Add(durationBeforeFlush)
+	if item, exists := x.items[id]; exists {
+		item.cacheEntry = cacheEntry
+		item.deadline = deadline
+		heap.Fix(&x.heap, item.index)
+	} else {
+		item = &expiryItem{cacheEntry: cacheEntry, deadline: deadline}
+		x.items[id] = item
+		heap.Push(&x.heap, item)
+	}
+}
+
+// remove removes the cache entry from the index.
+func (x *expiryIndex) remove(cacheEntry Entry) {
+	var id = keyOf(cacheEntry.Key())
+	if item, exists := x.items[id]; exists {
+		heap.Remove(&x.heap, item.index)
+		delete(x.items, id)
+	}
+}
+
+// next returns the indexed entry having the nearest deadline, and this deadline.
+// It returns nil if the index is empty.
+func (x *expiryIndex) next() (Entry, time.Time) {
+	if len(x.heap) == 0 {
+		return nil, time.Time{}
+	}
+	return x.heap[0].cacheEntry, x.heap[0].deadline
+}
+
+func newExpiryIndex(size uint32, clock Clock) *expiryIndex {
+	var nx = new(expiryIndex)
+	nx.clock = clock
+	nx.items = make(map[keyIdentity]*expiryItem, size)
+	nx.heap = make(expiryHeap, 0, size)
+	return nx
+}
@@ -0,0 +1,184 @@
+package LruCache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mmaFR/LruCache/clocktest"
+)
+
+func Test_expiryIndex(t *testing.T) {
+	var x = newExpiryIndex(4, SystemClock)
+	var a = NewEntry(NewStringKey("A"), "A entry", Second(30), Second(60))
+	var b = NewEntry(NewStringKey("B"), "B entry", Second(10), Second(60))
+	var c = NewEntry(NewStringKey("C"), "C entry", Second(20), Second(15))
+
+	if got, _ := x.next(); got != nil {
+		t.Errorf("next() = %v on an empty index, want nil", got)
+	}
+	x.add(a)
+	x.add(b)
+	x.add(c)
+	if got, _ := x.next(); got != b {
+		t.Errorf("next() = %v, want %v", got, b)
+	}
+	x.remove(b)
+	if got, _ := x.next(); got != c {
+		t.Errorf("next() = %v, want %v", got, c)
+	}
+	// Updating the deadline of an indexed entry moves it in the heap
+	a.(*entry).ttl = Second(1)
+	x.add(a)
+	if got, _ := x.next(); got != a {
+		t.Errorf("next() = %v, want %v", got, a)
+	}
+	if l := len(x.heap); l != 2 {
+		t.Errorf("Heap len = %d, want 2", l)
+	}
+	x.remove(b)
+	x.remove(a)
+	x.remove(c)
+	if got, _ := x.next(); got != nil {
+		t.Errorf("next() = %v on an empty index, want nil", got)
+	}
+}
+
+func Test_cache_HouseCleaning_AccessedEntry(t *testing.T) {
+	var c = NewCache(4).(*cache[keyIdentity])
+	var e = &entry{
+		key:          NewStringKey("A"),
+		value:        "A entry",
+		ttl:          Second(30),
+		maxAge:       Second(60),
+		accessTime:   time.Now().Add(Second(-40)),
+		creationTime: time.Now().Add(Second(-40)),
+	}
+	c.Add(e)
+	// The entry is indexed as expired, then accessed before the sweep
+	e.UpdateAccessTime()
+	got, _ := c.HouseCleaning()
+	if got != 0 {
+		t.Errorf("HouseCleaning() = %d, want 0", got)
+	}
+	if !c.Contains(e.Key()) {
+		t.Error("The accessed entry should not have been cleaned")
+	}
+	if _, deadline := c.expiry.next(); deadline.Before(time.Now().Add(Second(10))) {
+		t.Errorf("The entry deadline %v was not updated", deadline)
+	}
+}
+
+func Test_cache_HouseCleaning_LifetimeUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(Entry)
+		want   EvictionReason
+	}{
+		{
+			name:   "Shorten the TTL",
+			update: func(e Entry) { e.SetTTL(Second(1)) },
+			want:   EvictionTTL,
+		},
+		{
+			name:   "Shorten the max age",
+			update: func(e Entry) { e.SetMaxAge(Second(1)) },
+			want:   EvictionMaxAge,
+		},
+	}
+	for _, tt := range tests {
+		for _, constructor := range []func(uint32, ...CacheOption) Cache{NewCache, NewSyncCache} {
+			t.Run(tt.name, func(t *testing.T) {
+				var reasons []EvictionReason
+				var clock = clocktest.NewFakeClock(time.Now())
+				var c = constructor(4, WithClock(clock), OnEvict(func(e Entry, reason EvictionReason) {
+					reasons = append(reasons, reason)
+				}))
+				var e = NewEntry(NewStringKey("A"), "A entry", Second(30), Second(60), WithEntryClock(clock))
+				c.Add(e)
+				tt.update(e)
+				clock.Advance(Second(5))
+				if got, _ := c.HouseCleaning(); got != 1 {
+					t.Errorf("HouseCleaning() = %d, want 1", got)
+				}
+				if len(reasons) != 1 || reasons[0] != tt.want {
+					t.Errorf("Eviction reasons = %v, want [%s]", reasons, tt.want)
+				}
+				// A removed entry doesn't notify the cache anymore
+				e.SetTTL(Second(30))
+				if l := len(c.Keys()); l != 0 {
+					t.Errorf("Keys() len = %d, want 0", l)
+				}
+			})
+		}
+	}
+}
+
+// benchmarkHouseCleaning measures a sweep of a cache holding size entries of which expired are expired.
+func benchmarkHouseCleaning(b *testing.B, size int, expired int) {
+	var c = NewCache(uint32(size))
+	for i := 0; i < size-expired; i++ {
+		c.Add(NewEntry(NewIntKey(i), i, Second(3600), Second(3600)))
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		for i := size - expired; i < size; i++ {
+			c.Add(&entry{
+				key:          NewIntKey(i),
+				value:        i,
+				ttl:          Second(1),
+				maxAge:       Second(3600),
+				accessTime:   time.Now().Add(Second(-2)),
+				creationTime: time.Now().Add(Second(-2)),
+			})
+		}
+		b.StartTimer()
+		if got, _ := c.HouseCleaning(); got != uint32(expired) {
+			b.Fatalf("HouseCleaning() = %d, want %d", got, expired)
+		}
+	}
+}
+
+func BenchmarkHouseCleaning(b *testing.B) {
+	for _, size := range []int{10000, 100000, 1000000} {
+		for _, expired := range []int{10, 1000} {
+			b.Run(strconv.Itoa(size)+"Entries/"+strconv.Itoa(expired)+"Expired", func(b *testing.B) {
+				benchmarkHouseCleaning(b, size, expired)
+			})
+		}
+	}
+}
+
+func Test_cache_HouseCleaning_NoExpiration(t *testing.T) {
+	var clock = clocktest.NewFakeClock(time.Now())
+	var c = NewCache(4, WithClock(clock)).(*cache[keyIdentity])
+	var a = NewEntry(NewStringKey("A"), "A entry", NoExpiration, NoExpiration, WithEntryClock(clock))
+	var b = NewEntry(NewStringKey("B"), "B entry", 0, 0, WithEntryClock(clock))
+	var d = NewEntry(NewStringKey("D"), "D entry", Second(10), NoExpiration, WithEntryClock(clock))
+	c.Add(a)
+	c.Add(b)
+	c.Add(d)
+	if l := len(c.expiry.heap); l != 1 {
+		t.Errorf("Expiry index len = %d, want 1, the entries without expiry should not be indexed", l)
+	}
+
+	clock.Advance(Second(3600))
+	got, got1 := c.HouseCleaning()
+	if got != 1 || got1[0] != d {
+		t.Errorf("HouseCleaning() = %d, %v, want 1, [D]", got, got1)
+	}
+	if got := c.Get(NewStringKey("A")); got != a {
+		t.Errorf("Get() = %v, want %v", got, a)
+	}
+
+	// Setting a TTL on an entry without expiry indexes it
+	a.SetTTL(Second(10))
+	if l := len(c.expiry.heap); l != 1 {
+		t.Errorf("Expiry index len = %d, want 1", l)
+	}
+	a.SetTTL(NoExpiration)
+	if l := len(c.expiry.heap); l != 0 {
+		t.Errorf("Expiry index len = %d, want 0", l)
+	}
+}
@@ -0,0 +1,48 @@
+package LruCache
+
+import (
+	"errors"
+	"expvar"
+	"sync"
+)
+
+// ErrExpvarExists is returned by PublishExpvar when an expvar variable is already published with the same name.
+var ErrExpvarExists = errors.New("an expvar variable is already published with this name")
+
+// expvarMu serializes the PublishExpvar calls, so that the name check and the publication are atomic.
+var expvarMu sync.Mutex
+
+// expvarStats is the value published by PublishExpvar.
+type expvarStats struct {
+	Len      uint32  `json:"len"`
+	Capacity uint32  `json:"capacity"`
+	IsFull   bool    `json:"is_full"`
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// PublishExpvar publishes the cache length, capacity, fullness and hit/miss counters as the expvar variable name,
+// so they appear on /debug/vars. The values are read each time the variable is, so c must be safe for concurrent use,
+// like the caches returned by NewSyncCache and NewShardedCache.
+// It returns ErrExpvarExists if an expvar variable is already published with the same name.
+// The concurrent calls are safe, but expvar.Publish still panics if it is called directly with the same name.
+func PublishExpvar(name string, c Cache) error {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvar.Get(name) != nil {
+		return ErrExpvarExists
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		var s = c.Stats()
+		return expvarStats{
+			Len:      c.Len(),
+			Capacity: c.Capacity(),
+			IsFull:   c.IsFull(),
+			Hits:     s.Hits,
+			Misses:   s.Misses,
+			HitRatio: s.HitRatio(),
+		}
+	}))
+	return nil
+}
@@ -0,0 +1,73 @@
+package LruCache
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// expvarRuns numbers the published names, the expvar variables can't be unpublished between the test runs.
+var expvarRuns uint64
+
+// expvarName returns a name not yet published for the test.
+func expvarName(t *testing.T) string {
+	return fmt.Sprintf("%s-%d", t.Name(), atomic.AddUint64(&expvarRuns, 1))
+}
+
+func TestPublishExpvar(t *testing.T) {
+	var c = NewSyncCache(2)
+	var name = expvarName(t)
+	if err := PublishExpvar(name, c); err != nil {
+		t.Fatalf("PublishExpvar() error = %v, want nil", err)
+	}
+	if err := PublishExpvar(name, c); !errors.Is(err, ErrExpvarExists) {
+		t.Errorf("PublishExpvar() error = %v, want %v", err, ErrExpvarExists)
+	}
+
+	var read = func() expvarStats {
+		var v expvarStats
+		if err := json.Unmarshal([]byte(expvar.Get(name).String()), &v); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		return v
+	}
+	if got, want := read(), (expvarStats{Capacity: 2}); got != want {
+		t.Errorf("Published value = %+v, want %+v", got, want)
+	}
+
+	// The values are read lazily
+	c.Add(NewEntry(NewStringKey("A"), "A entry", time.Minute, time.Hour))
+	c.Add(NewEntry(NewStringKey("B"), "B entry", time.Minute, time.Hour))
+	c.Get(NewStringKey("A"))
+	c.Get(NewStringKey("C"))
+	if got, want := read(), (expvarStats{Len: 2, Capacity: 2, IsFull: true, Hits: 1, Misses: 1, HitRatio: 0.5}); got != want {
+		t.Errorf("Published value = %+v, want %+v", got, want)
+	}
+}
+
+func TestPublishExpvar_Concurrent(t *testing.T) {
+	var c = NewSyncCache(2)
+	var name = expvarName(t)
+	var published int32
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := PublishExpvar(name, c); err == nil {
+				atomic.AddInt32(&published, 1)
+			} else if !errors.Is(err, ErrExpvarExists) {
+				t.Errorf("PublishExpvar() error = %v, want %v", err, ErrExpvarExists)
+			}
+		}()
+	}
+	wg.Wait()
+	if published != 1 {
+		t.Errorf("%d concurrent PublishExpvar() calls succeeded, want 1", published)
+	}
+}
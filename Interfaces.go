@@ -2,23 +2,29 @@ package LruCache
 
 import (
 	"container/list"
+	"context"
 	"time"
 )
 
 // Cache is the cache interface.
 type Cache interface {
-	// Add adds a new entry in the cache and returns true if the oldest entry has been removed because the cache was full.
-	Add(entry Entry) bool
+	// Add adds a new entry in the cache, or replaces the entry having the same key.
+	// It returns what happened and the displaced entry: the replaced entry, the evicted entry, or nil.
+	// The entry is rejected when the cache is full and the eviction policy returns no victim.
+	// Adding again an entry already in the cache records an access to it, and returns EntryReplaced and no displaced entry.
+	Add(entry Entry) (AddResult, Entry)
 
 	// Get returns the entry corresponding to the requested key. It returns nil if the entry doesn't exist or expired.
+	// It updates the entry last access time and records the hit in the eviction policy,
+	// the default policy moves the entry to the front of the LRU list.
 	Get(key EntryKey) Entry
 
 	// GetWithoutAccessUpdate returns the entry corresponding to the requested key.
 	// It returns true if the entry exists.
-	// It doesn't the update the entry last access time.
+	// It doesn't the update the entry last access time, and doesn't move the entry in the LRU list.
 	GetWithoutAccessUpdate(key EntryKey) Entry
 
-	// GetLruEntry returns the oldest cache entry.
+	// GetLruEntry returns the entry the eviction policy would evict next, the oldest cache entry by default.
 	// It doesn't the update the entry last access time.
 	GetLruEntry() Entry
 
@@ -29,7 +35,7 @@ type Cache interface {
 	// It returns true if the entry exists, and the removed entry.
 	Remove(key EntryKey) (bool, Entry)
 
-	// RemoveLruEntry removes the least recently used cache entry, and returns it.
+	// RemoveLruEntry removes the entry the eviction policy would evict next, the least recently used one by default, and returns it.
 	RemoveLruEntry() Entry
 
 	// Keys returns the list of cache entries keys.
@@ -53,10 +59,26 @@ type Cache interface {
 
 	// IsFull returns true if the cache reaches its maximum capacity
 	IsFull() bool
+
+	// Stats returns the cache statistics.
+	Stats() Stats
+
+	// ResetStats sets the cache statistics to zero.
+	ResetStats()
+
+	// GetOrLoad returns the entry corresponding to the requested key like Get.
+	// On a miss, it calls the loader, adds a new entry holding the loaded value and returns it.
+	// The loader error is returned as is and nothing is added to the cache.
+	GetOrLoad(key EntryKey, loader Loader, options ...LoadOption) (Entry, error)
+
+	// GetOrLoadContext is like GetOrLoad, the context is given to the loader.
+	// The loader is not called if the context is already done.
+	GetOrLoadContext(ctx context.Context, key EntryKey, loader ContextLoader, options ...LoadOption) (Entry, error)
 }
 
 // Entry is the cache entry interface.
 type Entry interface {
+	EntryLifetime
 
 	// SetLruLink sets the link between the cache entry the LRU entry list.
 	SetLruLink(link *list.Element)
@@ -67,9 +89,14 @@ type Entry interface {
 	// Key returns the entry key.
 	Key() EntryKey
 
-	// Value returns the entry value.
+	// Value returns the entry value and updates the entry last access time.
+	// It doesn't move the entry in the cache LRU list, use Cache.Get for that.
 	Value() interface{}
+}
 
+// EntryLifetime is the interface managing the entry TTL, max age and expiry.
+// It is shared by Entry and TypedEntry.
+type EntryLifetime interface {
 	// SetTTL the entry TTL
 	SetTTL(ttl time.Duration)
 
@@ -82,7 +109,7 @@ type Entry interface {
 	// GetMaxAge returns the entry max age.
 	GetMaxAge() time.Duration
 
-	// UpdateAccessTime updates the entry last access time to time.Now()
+	// UpdateAccessTime updates the entry last access time to the entry clock current time.
 	UpdateAccessTime()
 
 	// GetAccessTime returns the entry last access time.
@@ -95,18 +122,23 @@ type Entry interface {
 	GetElapsedTimeFromLastAccess() time.Duration
 
 	// GetDelayToTTL returns the remaining delay before to reach the entry TTL.
+	// It returns NoExpiration if the entry has no TTL.
 	GetDelayToTTL() time.Duration
 
 	// GetDelayToMaxAge returns the remaining delay before to reach the entry max age.
+	// It returns NoExpiration if the entry has no max age.
 	GetDelayToMaxAge() time.Duration
 
 	// GetDurationBeforeFlush returns the remaining entry lifetime.
+	// It returns NoExpiration if the entry has neither TTL nor max age.
 	GetDurationBeforeFlush() time.Duration
 
 	// ExceedTTL returns true if the entry reached the TTL.
+	// An entry without TTL never reaches it.
 	ExceedTTL() bool
 
 	// ExceedMaxAge return true if the entry reached the max age.
+	// An entry without max age never reaches it.
 	ExceedMaxAge() bool
 
 	// IsExpired returns true is the cache entry expired
@@ -118,3 +150,153 @@ type EntryKey interface {
 	// String returns the key string representation.
 	String() string
 }
+
+// TypedCache is the type-safe cache interface.
+// The keys are stored natively, the lookups neither convert nor box them.
+// A typed cache shares the cache core of Cache, it accepts the same options.
+type TypedCache[K comparable, V any] interface {
+	// Add adds a new entry in the cache, or replaces the entry having the same key.
+	// It returns what happened and the displaced entry: the replaced entry, the evicted entry, or nil.
+	// Adding again an entry already in the cache records an access to it, and returns EntryReplaced and no displaced entry.
+	Add(entry TypedEntry[K, V]) (AddResult, TypedEntry[K, V])
+
+	// Get returns the entry corresponding to the requested key. It returns nil if the entry doesn't exist or expired.
+	// It updates the entry last access time and records the hit in the eviction policy.
+	Get(key K) TypedEntry[K, V]
+
+	// GetWithoutAccessUpdate returns the entry corresponding to the requested key.
+	// It doesn't the update the entry last access time, and doesn't move the entry in the LRU list.
+	GetWithoutAccessUpdate(key K) TypedEntry[K, V]
+
+	// GetLruEntry returns the entry the eviction policy would evict next, the oldest cache entry by default.
+	// It doesn't the update the entry last access time.
+	GetLruEntry() TypedEntry[K, V]
+
+	// Contains returns true if the cache contains an entry for the requested key.
+	Contains(key K) bool
+
+	// Remove removes the cache entry corresponding the requested key.
+	// It returns true if the entry exists, and the removed entry.
+	Remove(key K) (bool, TypedEntry[K, V])
+
+	// RemoveLruEntry removes the entry the eviction policy would evict next, the least recently used one by default, and returns it.
+	RemoveLruEntry() TypedEntry[K, V]
+
+	// Keys returns the list of cache entries keys.
+	Keys() []K
+
+	// Len returns the number of entries present in the cache.
+	Len() uint32
+
+	// Capacity returns the cache capacity.
+	Capacity() uint32
+
+	// Flush clears the cache and returns the number of entries flushed.
+	Flush() uint32
+
+	// Resize updates the cache capacity and returns the number of cache entries flushed during the downsizing and the slice of flushed entries.
+	Resize(size uint32) (uint32, []TypedEntry[K, V])
+
+	// HouseCleaning triggers the cache cleaning and removes the entries expired.
+	// It returns the number of flushed entries and the slice of them.
+	HouseCleaning() (uint32, []TypedEntry[K, V])
+
+	// IsFull returns true if the cache reaches its maximum capacity
+	IsFull() bool
+
+	// Stats returns the cache statistics.
+	Stats() Stats
+
+	// ResetStats sets the cache statistics to zero.
+	ResetStats()
+}
+
+// TypedEntry is the type-safe cache entry interface.
+type TypedEntry[K comparable, V any] interface {
+	EntryLifetime
+
+	// Key returns the entry key.
+	Key() K
+
+	// Value returns the entry value and updates the entry last access time.
+	// It doesn't move the entry in the cache LRU list, use TypedCache.Get for that.
+	Value() V
+}
+
+// TypedKey is the interface of the entry keys aware of their type.
+// The caches index such keys by their identity instead of their string representation,
+// so keys of different types sharing the same string representation don't collide.
+type TypedKey interface {
+	EntryKey
+
+	// Identity returns a comparable value identifying the key, including its type.
+	Identity() interface{}
+}
+
+// Janitor is the interface of the background cleaner removing the expired entries of a cache.
+type Janitor interface {
+	// Stop stops the janitor and waits for the running sweep to complete.
+	// It can be called several times.
+	Stop()
+
+	// Done returns a channel closed once the janitor goroutine exited.
+	Done() <-chan struct{}
+}
+
+// Clock is the interface providing the current time to the entries and the caches.
+// See the clocktest package for a manually advanced clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// EvictionPolicy is the interface of the policy choosing the entry evicted when the cache is full, see WithEvictionPolicy.
+// The cache owns the entries, the expiry index and the callbacks, the policy only orders the entries.
+// A policy is not safe for concurrent use, it is protected by the lock of the cache owning it.
+// A policy may also implement ResizablePolicy and ResettablePolicy.
+type EvictionPolicy interface {
+	// OnInsert records the entry added to the cache, a replaced entry is removed beforehand.
+	OnInsert(entry Entry)
+
+	// OnAccess records a hit on the entry.
+	OnAccess(entry Entry)
+
+	// OnRemove forgets the entry removed from the cache for the given reason.
+	OnRemove(entry Entry, reason EvictionReason)
+
+	// Victim returns the entry to evict to make room for the candidate, or nil if the policy tracks no entry.
+	// A full cache rejects the candidate when Victim returns nil, and Resize stops removing entries.
+	// The candidate is not yet inserted, it is nil when no entry is about to be added.
+	// The cache expects the same victim until the policy is notified of a change.
+	Victim(candidate Entry) Entry
+}
+
+// ResizablePolicy is the optional interface of the eviction policies notified of the cache capacity changes.
+type ResizablePolicy interface {
+	// Resize updates the capacity the policy sizes its segments with.
+	// It is called by Cache.Resize once the entries exceeding the new capacity are removed.
+	Resize(capacity uint32)
+}
+
+// ResettablePolicy is the optional interface of the eviction policies able to forget all their entries at once.
+// The cache flushing the entries of a policy not implementing it calls OnRemove for each entry instead.
+type ResettablePolicy interface {
+	// Reset forgets all the entries.
+	Reset()
+}
+
+// CoalescingLoader is the interface of the read-through loading sharing one loader call between the concurrent
+// misses of the same key.
+type CoalescingLoader interface {
+	// GetOrLoad returns the entry corresponding to the requested key like Cache.GetOrLoad.
+	// On a miss, the callers requesting the same key share the result of one loader call.
+	// A loader panic is returned to the callers as an error wrapping ErrLoaderPanic.
+	GetOrLoad(key EntryKey, loader Loader, options ...LoadOption) (Entry, error)
+
+	// GetOrLoadContext is like GetOrLoad, each caller stops waiting when its context is done.
+	// The loader context is cancelled once every waiting caller gave up.
+	GetOrLoadContext(ctx context.Context, key EntryKey, loader ContextLoader, options ...LoadOption) (Entry, error)
+
+	// Stats returns the number of loader calls and the number of deduplicated calls.
+	Stats() CoalescingStats
+}
@@ -0,0 +1,97 @@
+package LruCache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultJanitorInterval is the interval between the sweeps of a janitor started with an interval not positive.
+const DefaultJanitorInterval = time.Minute
+
+// janitor is a background cleaner calling HouseCleaning on a cache at a regular interval.
+// The sweep function calls HouseCleaning and reports the removed entries.
+type janitor struct {
+	sweep    func()
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// run sweeps the cache at each tick until the janitor is stopped or the context is done.
+func (j *janitor) run(ctx context.Context) {
+	var ticker = time.NewTicker(j.interval)
+	defer close(j.done)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			j.sweep()
+		case <-j.stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Stop stops the janitor and waits for the running sweep to complete.
+// It can be called several times.
+func (j *janitor) Stop() {
+	j.stopOnce.Do(func() {
+		close(j.stop)
+	})
+	<-j.done
+}
+
+// Done returns a channel closed once the janitor goroutine exited.
+func (j *janitor) Done() <-chan struct{} {
+	return j.done
+}
+
+// StartJanitor starts a goroutine calling c.HouseCleaning every interval, until Stop is called.
+// The report callback, if not nil, receives the entries removed by each sweep removing at least one entry.
+// The cache must be safe for concurrent use, see NewSyncCache and NewShardedCache.
+// The janitor sweeps every DefaultJanitorInterval if interval is not positive.
+func StartJanitor(c Cache, interval time.Duration, report func(uint32, []Entry)) Janitor {
+	return StartJanitorContext(context.Background(), c, interval, report)
+}
+
+// StartJanitorContext is like StartJanitor, the janitor also stops when the context is done.
+func StartJanitorContext(ctx context.Context, c Cache, interval time.Duration, report func(uint32, []Entry)) Janitor {
+	return startJanitor(ctx, interval, func() {
+		if numberOfDeletions, flushedEntries := c.HouseCleaning(); numberOfDeletions > 0 && report != nil {
+			report(numberOfDeletions, flushedEntries)
+		}
+	})
+}
+
+// StartTypedJanitor is StartJanitor for the typed caches, see NewTypedSyncCache and NewTypedShardedCache.
+func StartTypedJanitor[K comparable, V any](c TypedCache[K, V], interval time.Duration, report func(uint32, []TypedEntry[K, V])) Janitor {
+	return StartTypedJanitorContext(context.Background(), c, interval, report)
+}
+
+// StartTypedJanitorContext is StartJanitorContext for the typed caches.
+func StartTypedJanitorContext[K comparable, V any](ctx context.Context, c TypedCache[K, V], interval time.Duration, report func(uint32, []TypedEntry[K, V])) Janitor {
+	return startJanitor(ctx, interval, func() {
+		if numberOfDeletions, flushedEntries := c.HouseCleaning(); numberOfDeletions > 0 && report != nil {
+			report(numberOfDeletions, flushedEntries)
+		}
+	})
+}
+
+// startJanitor starts a goroutine calling sweep every interval, until the janitor is stopped or the context is done.
+// The interval is DefaultJanitorInterval if it is not positive.
+func startJanitor(ctx context.Context, interval time.Duration, sweep func()) Janitor {
+	if interval <= 0 {
+		interval = DefaultJanitorInterval
+	}
+	var nj = new(janitor)
+	nj.sweep = sweep
+	nj.interval = interval
+	nj.stop = make(chan struct{})
+	nj.done = make(chan struct{})
+	go nj.run(ctx)
+	return nj
+}
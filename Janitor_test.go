@@ -0,0 +1,86 @@
+package LruCache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStartJanitor(t *testing.T) {
+	var c = NewSyncCache(16)
+	var reports = make(chan []Entry, 16)
+	c.Add(NewEntry(NewStringKey("A"), "A entry", time.Millisecond, Second(15)))
+	c.Add(NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15)))
+	c.Add(NewEntry(NewStringKey("C"), "C entry", Second(10), time.Millisecond))
+
+	var j = StartJanitor(c, 2*time.Millisecond, func(n uint32, entries []Entry) {
+		if uint32(len(entries)) != n {
+			t.Errorf("Report called with %d entries, want %d", len(entries), n)
+		}
+		reports <- entries
+	})
+	defer j.Stop()
+
+	var cleaned = make(map[string]bool)
+	var timeout = time.After(time.Second)
+	for len(cleaned) < 2 {
+		select {
+		case entries := <-reports:
+			for _, e := range entries {
+				cleaned[e.Key().String()] = true
+			}
+		case <-timeout:
+			t.Fatalf("The janitor cleaned %v, want A and C", cleaned)
+		}
+	}
+	if !cleaned["A"] || !cleaned["C"] || cleaned["B"] {
+		t.Errorf("The janitor cleaned %v, want A and C", cleaned)
+	}
+	if got := c.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+}
+
+func Test_janitor_Stop(t *testing.T) {
+	var j = StartJanitor(NewSyncCache(16), time.Millisecond, nil)
+	j.Stop()
+	select {
+	case <-j.Done():
+	default:
+		t.Error("The janitor goroutine is still running after Stop")
+	}
+	// Stop can be called several times
+	j.Stop()
+}
+
+func TestStartJanitorContext(t *testing.T) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	var j = StartJanitorContext(ctx, NewSyncCache(16), time.Millisecond, nil)
+	cancel()
+	select {
+	case <-j.Done():
+	case <-time.After(time.Second):
+		t.Fatal("The janitor goroutine is still running after the context cancellation")
+	}
+	// Stop doesn't block once the context stopped the janitor
+	j.Stop()
+}
+
+func TestStartJanitor_IntervalNotPositive(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{name: "zero", interval: 0},
+		{name: "negative", interval: -time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var j = StartJanitor(NewSyncCache(16), tt.interval, nil)
+			defer j.Stop()
+			if got := j.(*janitor).interval; got != DefaultJanitorInterval {
+				t.Errorf("The janitor interval = %v, want %v", got, DefaultJanitorInterval)
+			}
+		})
+	}
+}
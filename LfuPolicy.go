@@ -0,0 +1,103 @@
+package LruCache
+
+import "container/list"
+
+// lfuBucket holds the entries accessed the same number of times, the most recently used at the front.
+type lfuBucket struct {
+	frequency uint64
+	entries   *list.List
+}
+
+// lfuNode links an entry to its frequency bucket.
+type lfuNode struct {
+	bucket  *list.Element
+	element *list.Element
+}
+
+// lfuPolicy evicts the least frequently used entry, the least recently used one among the entries having the same frequency.
+// The buckets are sorted by increasing frequency, so that insert, access, remove and victim run in constant time.
+type lfuPolicy struct {
+	buckets *list.List
+	nodes   map[keyIdentity]*lfuNode
+}
+
+// OnInsert adds the entry to the bucket of frequency 1.
+func (p *lfuPolicy) OnInsert(cacheEntry Entry) {
+	var front = p.buckets.Front()
+	if front == nil || front.Value.(*lfuBucket).frequency != 1 {
+		front = p.buckets.PushFront(&lfuBucket{frequency: 1, entries: list.New()})
+	}
+	p.nodes[keyOf(cacheEntry.Key())] = &lfuNode{
+		bucket:  front,
+		element: front.Value.(*lfuBucket).entries.PushFront(cacheEntry),
+	}
+}
+
+// OnAccess moves the entry to the bucket of the next frequency.
+func (p *lfuPolicy) OnAccess(cacheEntry Entry) {
+	var node, exists = p.nodes[keyOf(cacheEntry.Key())]
+	if !exists {
+		return
+	}
+	var current = node.bucket.Value.(*lfuBucket)
+	var next = node.bucket.Next()
+	if next == nil || next.Value.(*lfuBucket).frequency != current.frequency+1 {
+		next = p.buckets.InsertAfter(&lfuBucket{frequency: current.frequency + 1, entries: list.New()}, node.bucket)
+	}
+	p.unlink(node)
+	node.bucket = next
+	node.element = next.Value.(*lfuBucket).entries.PushFront(cacheEntry)
+}
+
+// OnRemove forgets the entry.
+func (p *lfuPolicy) OnRemove(cacheEntry Entry, _ EvictionReason) {
+	var key = keyOf(cacheEntry.Key())
+	if node, exists := p.nodes[key]; exists {
+		p.unlink(node)
+		delete(p.nodes, key)
+	}
+}
+
+// unlink removes the node from its bucket, and the bucket once empty.
+func (p *lfuPolicy) unlink(node *lfuNode) {
+	var bucket = node.bucket.Value.(*lfuBucket)
+	bucket.entries.Remove(node.element)
+	if bucket.entries.Len() == 0 {
+		p.buckets.Remove(node.bucket)
+	}
+}
+
+// Victim returns the least recently used entry of the lowest frequency bucket.
+func (p *lfuPolicy) Victim(_ Entry) Entry {
+	if p.buckets.Len() == 0 {
+		return nil
+	} else {
+		return p.buckets.Front().Value.(*lfuBucket).entries.Back().Value.(Entry)
+	}
+}
+
+// Reset forgets all the entries.
+func (p *lfuPolicy) Reset() {
+	p.buckets = list.New()
+	p.nodes = make(map[keyIdentity]*lfuNode)
+}
+
+// newLfuPolicy returns a new LFU eviction policy.
+func newLfuPolicy(capacity uint32) EvictionPolicy {
+	return &lfuPolicy{
+		buckets: list.New(),
+		nodes:   make(map[keyIdentity]*lfuNode, capacity),
+	}
+}
+
+// WithLfuEviction makes the cache evict the least frequently used entry instead of the least recently used one.
+// The entries having the same number of hits are evicted in LRU order. Replacing an entry resets its frequency.
+func WithLfuEviction() CacheOption {
+	return WithEvictionPolicy(newLfuPolicy)
+}
+
+// NewLfuCache returns a new cache able to store size entries, evicting the least frequently used entry.
+// The returned cache is not safe for concurrent use, use NewSyncCache with WithLfuEviction instead.
+func NewLfuCache(size uint32, options ...CacheOption) Cache {
+	return NewCache(size, append(options[:len(options):len(options)], WithLfuEviction())...)
+}
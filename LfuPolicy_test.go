@@ -0,0 +1,95 @@
+package LruCache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_lfuPolicy_EvictionOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario func(c Cache)
+		want     []int
+	}{
+		{
+			name: "Without hits, LRU order",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Add(intEntry(3))
+			},
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "Least frequently used first",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Add(intEntry(3))
+				for _, k := range []int{1, 1, 2, 3, 3, 3} {
+					c.Get(NewIntKey(k))
+				}
+			},
+			want: []int{2, 1, 3},
+		},
+		{
+			name: "LRU order among the same frequency",
+			scenario: func(c Cache) {
+				for _, k := range []int{1, 2, 3, 4} {
+					c.Add(intEntry(k))
+				}
+				for _, k := range []int{3, 1, 4, 2} {
+					c.Get(NewIntKey(k))
+				}
+			},
+			want: []int{3, 1, 4, 2},
+		},
+		{
+			name: "Replacing an entry resets its frequency",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(2))
+				c.Add(intEntry(1))
+			},
+			want: []int{1, 2},
+		},
+		{
+			name: "Only the hits update the frequency",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.GetWithoutAccessUpdate(NewIntKey(1))
+				c.Get(NewIntKey(3))
+			},
+			want: []int{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = NewLfuCache(8)
+			tt.scenario(c)
+			var got []int
+			for e := c.RemoveLruEntry(); e != nil; e = c.RemoveLruEntry() {
+				got = append(got, e.Value().(int))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eviction order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLfuCache_ScanResistance(t *testing.T) {
+	var trace = scanTrace(50, 4, 6)
+	var lru = replayTrace(NewCache(8), trace)
+	var lfu = replayTrace(NewLfuCache(8), trace)
+	if lfu <= lru {
+		t.Errorf("LFU hit ratio = %.2f, want more than the LRU hit ratio %.2f", lfu, lru)
+	}
+	if lfu < 0.5 {
+		t.Errorf("LFU hit ratio = %.2f, want at least 0.50", lfu)
+	}
+}
@@ -0,0 +1,219 @@
+package LruCache
+
+import "container/list"
+
+// lirsStatus is the status of a key in the LIRS policy.
+type lirsStatus uint8
+
+const (
+	lirsLir lirsStatus = iota
+	lirsHirResident
+	lirsHirNonResident
+)
+
+// lirsNode is a key known by the LIRS policy, its entry is nil once non resident.
+type lirsNode struct {
+	key        keyIdentity
+	cacheEntry Entry
+	status     lirsStatus
+	stack      *list.Element
+	queue      *list.Element
+	ghost      *list.Element
+}
+
+// lirsPolicy is the Low Inter-reference Recency Set policy.
+// The entries having a low inter-reference recency, the LIR entries, take most of the capacity and are never evicted,
+// the high inter-reference recency (HIR) resident entries share the rest in a FIFO queue the victims are taken from.
+// The stack orders the LIR, HIR resident and non resident keys by recency, its bottom is always a LIR entry:
+// an entry hit while in the stack, or added while its key is non resident in the stack, becomes LIR
+// and the bottom LIR entry becomes HIR. The number of non resident keys is bounded by the capacity.
+type lirsPolicy struct {
+	stack       *list.List
+	queue       *list.List
+	ghosts      *list.List
+	nodes       map[keyIdentity]*lirsNode
+	lirCount    int
+	lirCapacity int
+	capacity    int
+}
+
+// OnInsert adds the entry as LIR until the LIR entries fill their capacity, then as HIR resident.
+// An entry whose key is non resident in the stack becomes LIR.
+func (p *lirsPolicy) OnInsert(cacheEntry Entry) {
+	var key = keyOf(cacheEntry.Key())
+	var node, exists = p.nodes[key]
+	if exists {
+		p.ghosts.Remove(node.ghost)
+		node.ghost = nil
+		node.cacheEntry = cacheEntry
+		p.stack.MoveToFront(node.stack)
+		p.promote(node)
+		return
+	}
+	node = &lirsNode{key: key, cacheEntry: cacheEntry}
+	p.nodes[key] = node
+	node.stack = p.stack.PushFront(node)
+	if p.lirCount < p.lirCapacity {
+		node.status = lirsLir
+		p.lirCount++
+	} else {
+		node.status = lirsHirResident
+		node.queue = p.queue.PushBack(node)
+	}
+}
+
+// OnAccess moves the entry to the top of the stack, a HIR resident entry found in the stack becomes LIR.
+func (p *lirsPolicy) OnAccess(cacheEntry Entry) {
+	var node, exists = p.nodes[keyOf(cacheEntry.Key())]
+	if !exists {
+		return
+	}
+	switch {
+	case node.status == lirsLir:
+		p.stack.MoveToFront(node.stack)
+		p.prune()
+	case node.stack != nil:
+		p.stack.MoveToFront(node.stack)
+		p.queue.Remove(node.queue)
+		node.queue = nil
+		p.promote(node)
+	default:
+		node.stack = p.stack.PushFront(node)
+		p.queue.MoveToBack(node.queue)
+	}
+}
+
+// promote turns the node on top of the stack into a LIR entry, the bottom LIR entry becomes HIR resident if needed.
+func (p *lirsPolicy) promote(node *lirsNode) {
+	node.status = lirsLir
+	p.lirCount++
+	for p.lirCount > p.lirCapacity {
+		p.demote(p.stack.Back().Value.(*lirsNode))
+	}
+	p.prune()
+}
+
+// demote turns the LIR node into a HIR resident entry at the end of the queue, removing it from the stack.
+func (p *lirsPolicy) demote(node *lirsNode) {
+	p.stack.Remove(node.stack)
+	node.stack = nil
+	node.status = lirsHirResident
+	node.queue = p.queue.PushBack(node)
+	p.lirCount--
+	p.prune()
+}
+
+// prune removes the HIR keys from the bottom of the stack, the non resident ones are forgotten.
+func (p *lirsPolicy) prune() {
+	for back := p.stack.Back(); back != nil && back.Value.(*lirsNode).status != lirsLir; back = p.stack.Back() {
+		var node = back.Value.(*lirsNode)
+		p.stack.Remove(back)
+		node.stack = nil
+		if node.status == lirsHirNonResident {
+			p.forget(node)
+		}
+	}
+}
+
+// forget removes the node from the policy.
+func (p *lirsPolicy) forget(node *lirsNode) {
+	if node.stack != nil {
+		p.stack.Remove(node.stack)
+	}
+	if node.queue != nil {
+		p.queue.Remove(node.queue)
+	}
+	if node.ghost != nil {
+		p.ghosts.Remove(node.ghost)
+	}
+	if node.status == lirsLir {
+		p.lirCount--
+	}
+	delete(p.nodes, node.key)
+}
+
+// OnRemove forgets the entry. A HIR resident entry evicted to make room stays in the stack as a non resident key.
+func (p *lirsPolicy) OnRemove(cacheEntry Entry, reason EvictionReason) {
+	var node, exists = p.nodes[keyOf(cacheEntry.Key())]
+	if !exists || node.status == lirsHirNonResident {
+		return
+	}
+	if node.status == lirsHirResident && node.stack != nil && (reason == EvictionCapacity || reason == EvictionResize) {
+		p.queue.Remove(node.queue)
+		node.queue = nil
+		node.cacheEntry = nil
+		node.status = lirsHirNonResident
+		node.ghost = p.ghosts.PushBack(node)
+		for p.ghosts.Len() > p.capacity {
+			p.forget(p.ghosts.Front().Value.(*lirsNode))
+		}
+		return
+	}
+	p.forget(node)
+	p.prune()
+}
+
+// Victim returns the front of the HIR resident queue, or the bottom LIR entry of the stack when the queue is empty.
+func (p *lirsPolicy) Victim(_ Entry) Entry {
+	if p.queue.Len() > 0 {
+		return p.queue.Front().Value.(*lirsNode).cacheEntry
+	} else if p.stack.Len() > 0 {
+		return p.stack.Back().Value.(*lirsNode).cacheEntry
+	} else {
+		return nil
+	}
+}
+
+// Resize updates the LIR capacity, the LIR overflow is demoted from the bottom of the stack.
+func (p *lirsPolicy) Resize(capacity uint32) {
+	p.capacity = int(capacity)
+	p.lirCapacity = lirsLirCapacity(capacity)
+	for p.lirCount > p.lirCapacity {
+		p.demote(p.stack.Back().Value.(*lirsNode))
+	}
+	for p.ghosts.Len() > p.capacity {
+		p.forget(p.ghosts.Front().Value.(*lirsNode))
+	}
+}
+
+// Reset forgets all the entries and the non resident keys.
+func (p *lirsPolicy) Reset() {
+	p.stack = list.New()
+	p.queue = list.New()
+	p.ghosts = list.New()
+	p.nodes = make(map[keyIdentity]*lirsNode)
+	p.lirCount = 0
+}
+
+// lirsLirCapacity returns the LIR entries capacity, 99% of the cache capacity,
+// leaving at least one entry to the HIR resident entries when the capacity allows it.
+func lirsLirCapacity(capacity uint32) int {
+	var hir = capacity / 100
+	if hir < 1 {
+		hir = 1
+	}
+	if capacity <= hir {
+		return int(capacity)
+	}
+	return int(capacity - hir)
+}
+
+// newLirsPolicy returns a new LIRS eviction policy.
+func newLirsPolicy(capacity uint32) EvictionPolicy {
+	var np = &lirsPolicy{capacity: int(capacity), lirCapacity: lirsLirCapacity(capacity)}
+	np.Reset()
+	return np
+}
+
+// WithLirsEviction makes the cache use the LIRS policy: the entries reused at short intervals are kept,
+// whatever their recency, so that a loop over more entries than the capacity doesn't evict them all.
+// The policy remembers the keys of as many evicted entries as the cache capacity.
+func WithLirsEviction() CacheOption {
+	return WithEvictionPolicy(newLirsPolicy)
+}
+
+// NewLirsCache returns a new cache able to store size entries, using the LIRS policy.
+// The returned cache is not safe for concurrent use, use NewSyncCache with WithLirsEviction instead.
+func NewLirsCache(size uint32, options ...CacheOption) Cache {
+	return NewCache(size, append(options[:len(options):len(options)], WithLirsEviction())...)
+}
@@ -0,0 +1,236 @@
+package LruCache
+
+import (
+	"container/list"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// lirsNames returns the names of the nodes of the list from the front, the key followed by the status:
+// L for LIR, H for HIR resident, N for HIR non resident.
+func lirsNames(l *list.List) []string {
+	var names = make([]string, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		var node = e.Value.(*lirsNode)
+		names = append(names, strconv.Itoa(node.key.i)+[]string{"L", "H", "N"}[node.status])
+	}
+	return names
+}
+
+// checkLirsInvariants checks the LIRS bookkeeping against the cache entries.
+func checkLirsInvariants(t *testing.T, c *cache[keyIdentity]) {
+	t.Helper()
+	var p = c.policy.(*lirsPolicy)
+	if back := p.stack.Back(); back != nil && back.Value.(*lirsNode).status != lirsLir {
+		t.Fatalf("The stack bottom %v isn't LIR", lirsNames(p.stack))
+	}
+	var lir, resident int
+	for _, node := range p.nodes {
+		switch node.status {
+		case lirsLir:
+			lir++
+			resident++
+			if node.stack == nil || node.queue != nil {
+				t.Fatalf("The LIR key %v isn't only in the stack", node.key)
+			}
+		case lirsHirResident:
+			resident++
+			if node.queue == nil {
+				t.Fatalf("The HIR resident key %v isn't in the queue", node.key)
+			}
+		case lirsHirNonResident:
+			if node.stack == nil || node.ghost == nil || node.cacheEntry != nil {
+				t.Fatalf("The non resident key %v isn't a ghost in the stack", node.key)
+			}
+		}
+		if node.status != lirsHirNonResident && c.cacheMap[node.key] != node.cacheEntry {
+			t.Fatalf("The resident key %v entry isn't the cache entry", node.key)
+		}
+	}
+	if lir != p.lirCount || lir > p.lirCapacity {
+		t.Fatalf("%d LIR keys, want %d and at most %d", lir, p.lirCount, p.lirCapacity)
+	}
+	if resident != len(c.cacheMap) || p.queue.Len() != resident-lir {
+		t.Fatalf("%d resident keys and %d queued, want %d and %d", resident, p.queue.Len(), len(c.cacheMap), len(c.cacheMap)-lir)
+	}
+	if p.ghosts.Len() > p.capacity {
+		t.Fatalf("%d non resident keys, want at most %d", p.ghosts.Len(), p.capacity)
+	}
+}
+
+func Test_lirsPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario func(c Cache)
+		stack    []string
+		queue    []string
+	}{
+		{
+			name: "The first entries fill the LIR set",
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+			},
+			stack: []string{"4H", "3L", "2L", "1L"},
+			queue: []string{"4H"},
+		},
+		{
+			name: "The evicted HIR entry stays non resident in the stack",
+			scenario: func(c Cache) {
+				for k := 1; k <= 5; k++ {
+					c.Add(intEntry(k))
+				}
+			},
+			stack: []string{"5H", "4N", "3L", "2L", "1L"},
+			queue: []string{"5H"},
+		},
+		{
+			name: "A non resident key added again becomes LIR",
+			scenario: func(c Cache) {
+				for k := 1; k <= 5; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Add(intEntry(4))
+			},
+			stack: []string{"4L", "5N", "3L", "2L"},
+			queue: []string{"1H"},
+		},
+		{
+			name: "Hitting the bottom LIR entries prunes the stack",
+			scenario: func(c Cache) {
+				for k := 1; k <= 5; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(2))
+				c.Get(NewIntKey(3))
+			},
+			stack: []string{"3L", "2L", "1L"},
+			queue: []string{"5H"},
+		},
+		{
+			name: "A HIR hit outside the stack stays HIR",
+			scenario: func(c Cache) {
+				for k := 1; k <= 5; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(2))
+				c.Get(NewIntKey(3))
+				c.Get(NewIntKey(5))
+			},
+			stack: []string{"5H", "3L", "2L", "1L"},
+			queue: []string{"5H"},
+		},
+		{
+			name: "A HIR hit in the stack becomes LIR",
+			scenario: func(c Cache) {
+				for k := 1; k <= 5; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(2))
+				c.Get(NewIntKey(3))
+				c.Get(NewIntKey(5))
+				c.Get(NewIntKey(5))
+			},
+			stack: []string{"5L", "3L", "2L"},
+			queue: []string{"1H"},
+		},
+		{
+			name: "Explicit removals forget the key",
+			scenario: func(c Cache) {
+				for k := 1; k <= 5; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Remove(NewIntKey(5))
+				c.Remove(NewIntKey(1))
+			},
+			stack: []string{"4N", "3L", "2L"},
+			queue: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = newCache(4, WithLirsEviction())
+			tt.scenario(c)
+			var p = c.policy.(*lirsPolicy)
+			if got := lirsNames(p.stack); !reflect.DeepEqual(got, tt.stack) {
+				t.Errorf("Stack = %v, want %v", got, tt.stack)
+			}
+			if got := lirsNames(p.queue); !reflect.DeepEqual(got, tt.queue) {
+				t.Errorf("Queue = %v, want %v", got, tt.queue)
+			}
+			checkLirsInvariants(t, c)
+		})
+	}
+}
+
+func Test_lirsPolicy_Invariants(t *testing.T) {
+	var rnd = rand.New(rand.NewSource(7))
+	var c = newCache(16, WithLirsEviction())
+	for i := 0; i < 20000; i++ {
+		var key = rnd.Intn(48)
+		switch op := rnd.Intn(100); {
+		case op < 45:
+			c.Add(intEntry(key))
+		case op < 90:
+			c.Get(NewIntKey(key))
+		case op < 95:
+			c.Remove(NewIntKey(key))
+		case op < 98:
+			c.RemoveLruEntry()
+		default:
+			c.Resize(uint32(4 + rnd.Intn(24)))
+		}
+		checkLirsInvariants(t, c)
+	}
+}
+
+// loopTrace returns rounds of lookups of the keys from 0 to length-1, in order.
+func loopTrace(rounds int, length int) []int {
+	var trace = make([]int, 0, rounds*length)
+	for r := 0; r < rounds; r++ {
+		for k := 0; k < length; k++ {
+			trace = append(trace, k)
+		}
+	}
+	return trace
+}
+
+func TestLirsCache_LoopingPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity uint32
+		trace    []int
+		want     float64
+	}{
+		{name: "Loop slightly larger than the cache", capacity: 100, trace: loopTrace(20, 110), want: 0.8},
+		{name: "Loop twice as large as the cache", capacity: 100, trace: loopTrace(20, 200), want: 0.4},
+		{
+			name:     "Loop mixed with a hot set",
+			capacity: 100,
+			trace: func() []int {
+				var trace []int
+				for _, k := range loopTrace(20, 150) {
+					trace = append(trace, k, 1000+k%10)
+				}
+				return trace
+			}(),
+			want: 0.6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lru = replayTrace(NewCache(tt.capacity), tt.trace)
+			var lirs = replayTrace(NewLirsCache(tt.capacity), tt.trace)
+			t.Logf("LRU hit ratio = %.3f, LIRS hit ratio = %.3f", lru, lirs)
+			if lirs < tt.want || lirs <= lru {
+				t.Errorf("LIRS hit ratio = %.3f, want at least %.2f and more than the LRU hit ratio %.3f", lirs, tt.want, lru)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package LruCache
+
+import (
+	"context"
+	"time"
+)
+
+// Loader is the function loading the value of a missing cache entry.
+type Loader func(key EntryKey) (interface{}, error)
+
+// ContextLoader is the function loading the value of a missing cache entry, it should stop when the context is done.
+type ContextLoader func(ctx context.Context, key EntryKey) (interface{}, error)
+
+// loadOptions are the TTL and max age of the entries created by GetOrLoad.
+type loadOptions struct {
+	ttl    time.Duration
+	maxAge time.Duration
+}
+
+// LoadOption is a GetOrLoad option.
+type LoadOption func(*loadOptions)
+
+// LoadWithTTL sets the TTL of the entry created by GetOrLoad, instead of the cache default TTL.
+func LoadWithTTL(ttl time.Duration) LoadOption {
+	return func(lo *loadOptions) {
+		lo.ttl = ttl
+	}
+}
+
+// LoadWithMaxAge sets the max age of the entry created by GetOrLoad, instead of the cache default max age.
+func LoadWithMaxAge(maxAge time.Duration) LoadOption {
+	return func(lo *loadOptions) {
+		lo.maxAge = maxAge
+	}
+}
+
+// WithDefaultLifetime sets the TTL and the max age of the entries created by GetOrLoad.
+// By default these entries have no expiry, see NoExpiration.
+func WithDefaultLifetime(ttl time.Duration, maxAge time.Duration) CacheOption {
+	return func(s *cacheSettings) {
+		s.loadDefaults = loadOptions{ttl: ttl, maxAge: maxAge}
+	}
+}
+
+// contextLoader adapts a Loader to the ContextLoader signature.
+func contextLoader(loader Loader) ContextLoader {
+	return func(_ context.Context, key EntryKey) (interface{}, error) {
+		return loader(key)
+	}
+}
+
+// entryLoader is implemented by the caches able to load and add an entry without looking it up first,
+// so that the CoalescingLoader counts one miss per caller.
+type entryLoader interface {
+	loadEntry(ctx context.Context, key EntryKey, loader ContextLoader, options []LoadOption) (Entry, error)
+}
+
+// getOrLoad returns the cache entry corresponding to the requested key, or loads its value, adds the new entry in the
+// cache and returns it. The loader is called without holding any cache lock, and its error is returned as is.
+func getOrLoad(ctx context.Context, c Cache, defaults loadOptions, clock Clock, key EntryKey, loader ContextLoader, options []LoadOption) (Entry, error) {
+	if cacheEntry := c.Get(key); cacheEntry != nil {
+		return cacheEntry, nil
+	}
+	return loadEntry(ctx, c, defaults, clock, key, loader, options)
+}
+
+// loadEntry loads the value of the requested key, adds the new entry in the cache and returns it.
+func loadEntry(ctx context.Context, c Cache, defaults loadOptions, clock Clock, key EntryKey, loader ContextLoader, options []LoadOption) (Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	value, err := loader(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	var lo = defaults
+	for _, option := range options {
+		option(&lo)
+	}
+	var cacheEntry = NewEntry(key, value, lo.ttl, lo.maxAge, WithEntryClock(clock))
+	c.Add(cacheEntry)
+	return cacheEntry, nil
+}
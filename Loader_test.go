@@ -0,0 +1,158 @@
+package LruCache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mmaFR/LruCache/clocktest"
+)
+
+var loaderConstructors = map[string]func(clock Clock, options ...CacheOption) Cache{
+	"cache": func(clock Clock, options ...CacheOption) Cache {
+		return NewCache(16, append(options, WithClock(clock))...)
+	},
+	"syncCache": func(clock Clock, options ...CacheOption) Cache {
+		return NewSyncCache(16, append(options, WithClock(clock))...)
+	},
+	"shardedCache": func(clock Clock, options ...CacheOption) Cache {
+		return NewShardedCache(4, 16, append(options, WithClock(clock))...)
+	},
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	var errLoad = errors.New("load error")
+	tests := []struct {
+		name       string
+		present    bool
+		loadErr    error
+		options    []LoadOption
+		want       interface{}
+		wantErr    error
+		wantCalls  int
+		wantTTL    time.Duration
+		wantMaxAge time.Duration
+	}{
+		{
+			name:       "Hit doesn't call the loader",
+			present:    true,
+			want:       "cached",
+			wantCalls:  0,
+			wantTTL:    Second(10),
+			wantMaxAge: Second(15),
+		},
+		{
+			name:       "Miss calls the loader and uses the default lifetime",
+			want:       "loaded",
+			wantCalls:  1,
+			wantTTL:    Second(30),
+			wantMaxAge: Second(60),
+		},
+		{
+			name:       "Miss calls the loader and uses the per-call lifetime",
+			options:    []LoadOption{LoadWithTTL(Second(1)), LoadWithMaxAge(Second(2))},
+			want:       "loaded",
+			wantCalls:  1,
+			wantTTL:    Second(1),
+			wantMaxAge: Second(2),
+		},
+		{
+			name:      "Loader error is returned and not cached",
+			loadErr:   errLoad,
+			wantErr:   errLoad,
+			wantCalls: 1,
+		},
+	}
+	for name, constructor := range loaderConstructors {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				var clock = clocktest.NewFakeClock(time.Now())
+				var c = constructor(clock, WithDefaultLifetime(Second(30), Second(60)))
+				var key = NewStringKey("A")
+				if tt.present {
+					c.Add(NewEntry(key, "cached", Second(10), Second(15), WithEntryClock(clock)))
+				}
+				var calls int
+				got, err := c.GetOrLoad(key, func(k EntryKey) (interface{}, error) {
+					calls++
+					if k != key {
+						t.Errorf("Loader called with %v, want %v", k, key)
+					}
+					if tt.loadErr != nil {
+						return nil, tt.loadErr
+					}
+					return "loaded", nil
+				}, tt.options...)
+				if calls != tt.wantCalls {
+					t.Errorf("Loader called %d times, want %d", calls, tt.wantCalls)
+				}
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetOrLoad() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantErr != nil {
+					if got != nil {
+						t.Errorf("GetOrLoad() = %v, want nil", got)
+					}
+					if c.Contains(key) {
+						t.Error("The failed load should not be cached")
+					}
+					return
+				}
+				if got == nil || got.Value() != tt.want {
+					t.Fatalf("GetOrLoad() = %v, want %v", got, tt.want)
+				}
+				if got.GetTTL() != tt.wantTTL || got.GetMaxAge() != tt.wantMaxAge {
+					t.Errorf("GetOrLoad() lifetime = %v, %v, want %v, %v", got.GetTTL(), got.GetMaxAge(), tt.wantTTL, tt.wantMaxAge)
+				}
+				if cached := c.GetWithoutAccessUpdate(key); cached != got {
+					t.Errorf("GetWithoutAccessUpdate() = %v, want %v", cached, got)
+				}
+			})
+		}
+	}
+}
+
+func TestCache_GetOrLoad_DefaultNoExpiration(t *testing.T) {
+	var clock = clocktest.NewFakeClock(time.Now())
+	var c = NewCache(16, WithClock(clock))
+	got, _ := c.GetOrLoad(NewIntKey(1), func(EntryKey) (interface{}, error) {
+		return 1, nil
+	})
+	clock.Advance(Second(3600 * 24))
+	if got.IsExpired() || c.Get(NewIntKey(1)) != got {
+		t.Error("The loaded entry should not expire without default lifetime")
+	}
+}
+
+func TestCache_GetOrLoadContext(t *testing.T) {
+	for name, constructor := range loaderConstructors {
+		t.Run(name, func(t *testing.T) {
+			var c = constructor(SystemClock)
+			var ctx, cancel = context.WithCancel(context.Background())
+			type ctxKey struct{}
+			ctx = context.WithValue(ctx, ctxKey{}, "value")
+
+			got, err := c.GetOrLoadContext(ctx, NewIntKey(1), func(ctx context.Context, k EntryKey) (interface{}, error) {
+				return ctx.Value(ctxKey{}), nil
+			})
+			if err != nil || got == nil || got.Value() != "value" {
+				t.Errorf("GetOrLoadContext() = %v, %v, want value", got, err)
+			}
+
+			cancel()
+			got, err = c.GetOrLoadContext(ctx, NewIntKey(2), func(ctx context.Context, k EntryKey) (interface{}, error) {
+				t.Error("The loader should not be called with a cancelled context")
+				return nil, nil
+			})
+			if !errors.Is(err, context.Canceled) || got != nil {
+				t.Errorf("GetOrLoadContext() = %v, %v, want nil, %v", got, err, context.Canceled)
+			}
+
+			// A hit doesn't need the context
+			if got, err = c.GetOrLoadContext(ctx, NewIntKey(1), nil); err != nil || got == nil {
+				t.Errorf("GetOrLoadContext() = %v, %v on a hit, want the cached entry", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package LruCache
+
+import (
+	"context"
+	"hash/maphash"
+	"sync/atomic"
+)
+
+// shardedCache is a cache object safe for concurrent use which spreads its entries over several
+// independent shards to reduce the lock contention.
+// Each shard is a syncCache holding a part of the total capacity, the entries are dispatched
+// between the shards according to the hash of their key identity.
+// The victims of the shards using the LRU policy are compared by access time, the other policies victims can't be compared
+// so the shards are taken in turn, starting from next.
+type shardedCache[K comparable] struct {
+	shards  []*syncCache[K]
+	seed    maphash.Seed
+	recency bool
+	next    uint32
+}
+
+// shard returns the shard in charge of the requested key identity.
+func (sc *shardedCache[K]) shard(key K) *syncCache[K] {
+	return sc.shards[maphash.Comparable(sc.seed, key)%uint64(len(sc.shards))]
+}
+
+// shardOf returns the shard in charge of the requested key.
+func (sc *shardedCache[K]) shardOf(key EntryKey) *syncCache[K] {
+	return sc.shard(sc.shards[0].cache.identify(key))
+}
+
+// Add adds a new entry in the cache, or replaces the entry having the same key.
+// It returns what happened and the displaced entry: the replaced entry, the evicted entry, or nil.
+// The evicted entry is the victim of the eviction policy of the shard in charge of the entry key.
+func (sc *shardedCache[K]) Add(cacheEntry Entry) (AddResult, Entry) {
+	return sc.shardOf(cacheEntry.Key()).Add(cacheEntry)
+}
+
+// Get returns the entry corresponding to the requested key. It returns nil if the entry doesn't exist or expired.
+// It updates the entry last access time and records the hit in the eviction policy,
+// the default policy moves the entry to the front of the LRU list.
+func (sc *shardedCache[K]) Get(key EntryKey) Entry {
+	return sc.shardOf(key).Get(key)
+}
+
+// getKey is Get for the key identity.
+func (sc *shardedCache[K]) getKey(key K) Entry {
+	return sc.shard(key).getKey(key)
+}
+
+// GetWithoutAccessUpdate returns the entry corresponding to the requested key.
+// It returns true if the entry exists.
+// It doesn't the update the entry last access time, and doesn't move the entry in the LRU list.
+func (sc *shardedCache[K]) GetWithoutAccessUpdate(key EntryKey) Entry {
+	return sc.shardOf(key).GetWithoutAccessUpdate(key)
+}
+
+// getKeyWithoutAccessUpdate is GetWithoutAccessUpdate for the key identity.
+func (sc *shardedCache[K]) getKeyWithoutAccessUpdate(key K) Entry {
+	return sc.shard(key).getKeyWithoutAccessUpdate(key)
+}
+
+// GetLruEntry returns the entry RemoveLruEntry would remove next, the victim of one shard.
+// With the default policy, it is the least recently used entry among the entries each shard would evict next.
+// With the other policies, it is the victim of the next shard in turn having entries.
+// It doesn't the update the entry last access time.
+// The shards are inspected one after the other, so the result is approximate under concurrent use.
+func (sc *shardedCache[K]) GetLruEntry() Entry {
+	var _, lruEntry = sc.lruShard()
+	return lruEntry
+}
+
+// lruShard returns the index of the shard whose victim RemoveLruEntry removes next, and this victim,
+// or -1 and nil if the cache is empty.
+func (sc *shardedCache[K]) lruShard() (int, Entry) {
+	var lruShard = -1
+	var lruEntry Entry
+	var first = int(atomic.LoadUint32(&sc.next))
+	for n := 0; n < len(sc.shards); n++ {
+		var i = (first + n) % len(sc.shards)
+		if shardLruEntry := sc.shards[i].GetLruEntry(); shardLruEntry != nil {
+			if !sc.recency {
+				return i, shardLruEntry
+			}
+			if lruEntry == nil || shardLruEntry.GetAccessTime().Before(lruEntry.GetAccessTime()) {
+				lruShard = i
+				lruEntry = shardLruEntry
+			}
+		}
+	}
+	return lruShard, lruEntry
+}
+
+// Contains returns true if the cache contains an entry for the requested key.
+func (sc *shardedCache[K]) Contains(key EntryKey) bool {
+	return sc.shardOf(key).Contains(key)
+}
+
+// containsKey is Contains for the key identity.
+func (sc *shardedCache[K]) containsKey(key K) bool {
+	return sc.shard(key).containsKey(key)
+}
+
+// Remove removes the cache entry corresponding the requested key.
+// It returns true if the entry exists, and the removed entry.
+func (sc *shardedCache[K]) Remove(key EntryKey) (bool, Entry) {
+	return sc.shardOf(key).Remove(key)
+}
+
+// removeKey is Remove for the key identity.
+func (sc *shardedCache[K]) removeKey(key K) (bool, Entry) {
+	return sc.shard(key).removeKey(key)
+}
+
+// RemoveLruEntry removes the victim of one shard and returns it, see GetLruEntry.
+// The shards are inspected one after the other, so the result is approximate under concurrent use.
+func (sc *shardedCache[K]) RemoveLruEntry() Entry {
+	if lruShard, _ := sc.lruShard(); lruShard >= 0 {
+		atomic.StoreUint32(&sc.next, uint32(lruShard+1)%uint32(len(sc.shards)))
+		return sc.shards[lruShard].RemoveLruEntry()
+	}
+	return nil
+}
+
+// Keys returns the list of cache entries keys.
+func (sc *shardedCache[K]) Keys() []EntryKey {
+	var ek = make([]EntryKey, 0, sc.Len())
+	for _, shard := range sc.shards {
+		ek = append(ek, shard.Keys()...)
+	}
+	return ek
+}
+
+// Len returns the number of entries present in the cache.
+func (sc *shardedCache[K]) Len() uint32 {
+	var l uint32
+	for _, shard := range sc.shards {
+		l += shard.Len()
+	}
+	return l
+}
+
+// Capacity returns the cache capacity.
+func (sc *shardedCache[K]) Capacity() uint32 {
+	var c uint32
+	for _, shard := range sc.shards {
+		c += shard.Capacity()
+	}
+	return c
+}
+
+// Flush clears the cache and returns the number of entries flushed.
+func (sc *shardedCache[K]) Flush() uint32 {
+	var numberOfEntries uint32
+	for _, shard := range sc.shards {
+		numberOfEntries += shard.Flush()
+	}
+	return numberOfEntries
+}
+
+// Resize updates the cache capacity and returns the number of cache entries flushed during the downsizing and the slice of flushed entries.
+// The new capacity is spread between the shards, each shard evicts the victims of its own eviction policy.
+// The number of shards doesn't change: below one entry per shard, the shards left without capacity
+// reject the entries of their keys, see EntryRejected.
+func (sc *shardedCache[K]) Resize(size uint32) (uint32, []Entry) {
+	var flushedEntries = make([]Entry, 0)
+	var numberOfDeletion uint32
+	for i, capacity := range shardCapacities(size, uint32(len(sc.shards))) {
+		n, entries := sc.shards[i].Resize(capacity)
+		numberOfDeletion += n
+		flushedEntries = append(flushedEntries, entries...)
+	}
+	return numberOfDeletion, flushedEntries
+}
+
+// HouseCleaning triggers the cache cleaning and removes the entries expired.
+// It returns the number of flushed entries and the slice of them.
+func (sc *shardedCache[K]) HouseCleaning() (uint32, []Entry) {
+	var flushedEntries = make([]Entry, 0)
+	var numberOfDeletions uint32
+	for _, shard := range sc.shards {
+		n, entries := shard.HouseCleaning()
+		numberOfDeletions += n
+		flushedEntries = append(flushedEntries, entries...)
+	}
+	return numberOfDeletions, flushedEntries
+}
+
+// IsFull returns true if the cache reaches its maximum capacity
+func (sc *shardedCache[K]) IsFull() bool {
+	if sc.Len() >= sc.Capacity() {
+		return true
+	} else {
+		return false
+	}
+}
+
+// Stats returns the cache statistics, the sum of the shards statistics.
+func (sc *shardedCache[K]) Stats() Stats {
+	var stats Stats
+	for _, shard := range sc.shards {
+		stats = stats.add(shard.Stats())
+	}
+	return stats
+}
+
+// ResetStats sets the cache statistics to zero.
+func (sc *shardedCache[K]) ResetStats() {
+	for _, shard := range sc.shards {
+		shard.ResetStats()
+	}
+}
+
+// GetOrLoad returns the entry corresponding to the requested key like Get.
+// On a miss, it calls the loader, adds a new entry holding the loaded value and returns it.
+// The loader error is returned as is and nothing is added to the cache.
+func (sc *shardedCache[K]) GetOrLoad(key EntryKey, loader Loader, options ...LoadOption) (Entry, error) {
+	return sc.shardOf(key).GetOrLoad(key, loader, options...)
+}
+
+// GetOrLoadContext is like GetOrLoad, the context is given to the loader.
+// The loader is not called if the context is already done.
+func (sc *shardedCache[K]) GetOrLoadContext(ctx context.Context, key EntryKey, loader ContextLoader, options ...LoadOption) (Entry, error) {
+	return sc.shardOf(key).GetOrLoadContext(ctx, key, loader, options...)
+}
+
+// loadEntry loads the value of the requested key, adds the new entry in the shard in charge of the key and returns it.
+func (sc *shardedCache[K]) loadEntry(ctx context.Context, key EntryKey, loader ContextLoader, options []LoadOption) (Entry, error) {
+	return sc.shardOf(key).loadEntry(ctx, key, loader, options)
+}
+
+// shardCapacities spreads capacity between n shards, the first shards receive the remainder.
+func shardCapacities(capacity uint32, n uint32) []uint32 {
+	var capacities = make([]uint32, n)
+	for i := uint32(0); i < n; i++ {
+		capacities[i] = capacity / n
+		if i < capacity%n {
+			capacities[i]++
+		}
+	}
+	return capacities
+}
+
+// NewShardedCache returns a new cache safe for concurrent use, able to store capacity entries
+// spread over the requested number of shards.
+// The number of shards is at least 1 and at most capacity, so that every shard can hold an entry.
+// The options are applied to every shard.
+func NewShardedCache(shards uint32, capacity uint32, options ...CacheOption) Cache {
+	return newShardedCache(shards, capacity, keyOf, options)
+}
+
+// newShardedCache returns a new sharded cache whose shards are indexed by the value identify returns for the entry keys.
+func newShardedCache[K comparable](shards uint32, capacity uint32, identify func(EntryKey) K, options []CacheOption) *shardedCache[K] {
+	if shards > capacity {
+		shards = capacity
+	}
+	if shards == 0 {
+		shards = 1
+	}
+	var nc = new(shardedCache[K])
+	nc.shards = make([]*syncCache[K], shards)
+	nc.seed = maphash.MakeSeed()
+	for i, shardCapacity := range shardCapacities(capacity, shards) {
+		nc.shards[i] = newSyncCache(newKeyedCache(shardCapacity, identify, options))
+	}
+	_, nc.recency = nc.shards[0].cache.policy.(*lruPolicy)
+	return nc
+}
@@ -0,0 +1,325 @@
+package LruCache
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmaFR/LruCache/clocktest"
+)
+
+func TestNewShardedCache(t *testing.T) {
+	type args struct {
+		shards   uint32
+		capacity uint32
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  int
+		want1 uint32
+	}{
+		{
+			name:  "Create sharded cache",
+			args:  args{shards: 8, capacity: 128},
+			want:  8,
+			want1: 128,
+		},
+		{
+			name:  "Create sharded cache with a capacity not divisible by the number of shards",
+			args:  args{shards: 8, capacity: 130},
+			want:  8,
+			want1: 130,
+		},
+		{
+			name:  "Create sharded cache with more shards than capacity",
+			args:  args{shards: 8, capacity: 4},
+			want:  4,
+			want1: 4,
+		},
+		{
+			name:  "Create sharded cache without shard",
+			args:  args{shards: 0, capacity: 16},
+			want:  1,
+			want1: 16,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewShardedCache(tt.args.shards, tt.args.capacity)
+			if l := len(got.(*shardedCache[keyIdentity]).shards); l != tt.want {
+				t.Errorf("Number of shards = %d, want %d", l, tt.want)
+			}
+			if c := got.Capacity(); c != tt.want1 {
+				t.Errorf("Capacity() = %d, want %d", c, tt.want1)
+			}
+		})
+	}
+}
+
+func Test_shardCapacities(t *testing.T) {
+	type args struct {
+		capacity uint32
+		n        uint32
+	}
+	tests := []struct {
+		name string
+		args args
+		want []uint32
+	}{
+		{
+			name: "Divisible capacity",
+			args: args{capacity: 16, n: 4},
+			want: []uint32{4, 4, 4, 4},
+		},
+		{
+			name: "Capacity with a remainder",
+			args: args{capacity: 10, n: 4},
+			want: []uint32{3, 3, 2, 2},
+		},
+		{
+			name: "Capacity lower than the number of shards",
+			args: args{capacity: 2, n: 4},
+			want: []uint32{1, 1, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shardCapacities(tt.args.capacity, tt.args.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shardCapacities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_shardedCache_Operations(t *testing.T) {
+	var c = NewShardedCache(4, 64)
+	var keys = make([]string, 0, 32)
+	for i := 0; i < 32; i++ {
+		keys = append(keys, strconv.Itoa(i))
+		if got, _ := c.Add(NewEntry(NewStringKey(keys[i]), i, Second(10), Second(15))); got != EntryInserted {
+			t.Errorf("Add() = %s, want %s", got, EntryInserted)
+		}
+	}
+	if got := c.Len(); got != 32 {
+		t.Errorf("Len() = %d, want 32", got)
+	}
+	for i, k := range keys {
+		got := c.Get(NewStringKey(k))
+		if got == nil || got.Value() != i {
+			t.Errorf("Get(%s) = %v, want %d", k, got, i)
+		}
+	}
+	var gotKeys = make([]string, 0, 32)
+	for _, k := range c.Keys() {
+		gotKeys = append(gotKeys, k.String())
+	}
+	sort.Strings(gotKeys)
+	sort.Strings(keys)
+	if !reflect.DeepEqual(gotKeys, keys) {
+		t.Errorf("Keys() = %v, want %v", gotKeys, keys)
+	}
+
+	// The least recently used entry is the first one read by Get
+	if got := c.GetLruEntry(); got == nil || got.Key().String() != "0" {
+		t.Errorf("GetLruEntry() = %v, want 0", got)
+	}
+	if got := c.RemoveLruEntry(); got == nil || got.Key().String() != "0" {
+		t.Errorf("RemoveLruEntry() = %v, want 0", got)
+	}
+	if got, _ := c.Remove(NewStringKey("1")); !got {
+		t.Error("Remove() = false, want true")
+	}
+	if c.Contains(NewStringKey("1")) {
+		t.Error("Contains() = true, want false")
+	}
+
+	got, got1 := c.Resize(8)
+	if want := 30 - c.Len(); got != want || uint32(len(got1)) != got {
+		t.Errorf("Resize() = %d, %d entries, want %d", got, len(got1), want)
+	}
+	if got := c.Capacity(); got != 8 {
+		t.Errorf("Capacity() = %d, want 8", got)
+	}
+	for _, shard := range c.(*shardedCache[keyIdentity]).shards {
+		if shard.Capacity() != 2 {
+			t.Errorf("Shard capacity = %d, want 2", shard.Capacity())
+		}
+		if shard.Len() > shard.Capacity() {
+			t.Errorf("Shard len = %d is greater than its capacity %d", shard.Len(), shard.Capacity())
+		}
+	}
+	if l := c.Len(); c.Flush() != l {
+		t.Errorf("Flush() doesn't return %d", l)
+	}
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() = %d after Flush(), want 0", got)
+	}
+}
+
+func Test_shardedCache_HouseCleaning(t *testing.T) {
+	var clock = clocktest.NewFakeClock(time.Now())
+	var c = NewShardedCache(4, 64, WithClock(clock))
+	for i := 0; i < 16; i++ {
+		var ttl = Second(10)
+		if i%2 == 0 {
+			ttl = Second(1)
+		}
+		c.Add(NewEntry(NewIntKey(i), i, ttl, Second(15), WithEntryClock(clock)))
+	}
+	clock.Advance(Second(2))
+	got, got1 := c.HouseCleaning()
+	if got != 8 || len(got1) != 8 {
+		t.Errorf("HouseCleaning() = %d, %d entries, want 8", got, len(got1))
+	}
+	for _, e := range got1 {
+		if e.Value().(int)%2 != 0 {
+			t.Errorf("Entry %v should not have been cleaned", e.Key())
+		}
+	}
+}
+
+func Test_shardedCache_ResizeBelowShards(t *testing.T) {
+	var c = NewShardedCache(4, 8)
+	if n, _ := c.Resize(2); n != 0 {
+		t.Errorf("Resize() = %d on an empty cache, want 0", n)
+	}
+	var rejected int
+	for i := 0; i < 100; i++ {
+		if result, _ := c.Add(intEntry(i)); result == EntryRejected {
+			rejected++
+		}
+	}
+	if l := c.Len(); l != 2 || c.Capacity() != 2 {
+		t.Errorf("Len() = %d, Capacity() = %d, want 2, 2", l, c.Capacity())
+	}
+	if rejected == 0 {
+		t.Error("No entry rejected by the shards without capacity")
+	}
+	for _, shard := range c.(*shardedCache[keyIdentity]).shards {
+		if shard.Len() > shard.Capacity() {
+			t.Errorf("Shard len = %d is greater than its capacity %d", shard.Len(), shard.Capacity())
+		}
+	}
+	if !c.IsFull() {
+		t.Error("IsFull() = false, want true")
+	}
+}
+
+func Test_shardedCache_RemoveLruEntryPolicy(t *testing.T) {
+	var c = NewShardedCache(4, 64, WithLfuEviction())
+	var sc = c.(*shardedCache[keyIdentity])
+	for i := 0; i < 16; i++ {
+		c.Add(intEntry(i))
+		c.Get(NewIntKey(i))
+	}
+	var shardIndex = func(key EntryKey) int {
+		for i, shard := range sc.shards {
+			if shard == sc.shardOf(key) {
+				return i
+			}
+		}
+		return -1
+	}
+	var previous = -1
+	for n := 0; n < 4; n++ {
+		var victim = c.GetLruEntry()
+		var shard = sc.shardOf(victim.Key())
+		if want := shard.GetLruEntry(); victim != want {
+			t.Errorf("GetLruEntry() = %v, want the victim of its shard %v", victim.Key(), want.Key())
+		}
+		if got := c.RemoveLruEntry(); got != victim {
+			t.Fatalf("RemoveLruEntry() = %v, want %v", got.Key(), victim.Key())
+		}
+		if i := shardIndex(victim.Key()); i == previous {
+			t.Errorf("RemoveLruEntry() removed twice in a row from the shard %d, want the shards in turn", i)
+		} else {
+			previous = i
+		}
+	}
+	if removed := drainVictims(t, c); removed != 12 {
+		t.Errorf("%d entries drained, want 12", removed)
+	}
+}
+
+func Test_shardedCache_Stress(t *testing.T) {
+	const goroutines = 16
+	const iterations = 2000
+	const keys = 256
+
+	var c = NewShardedCache(8, 128)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			var r = rand.New(rand.NewSource(seed))
+			for i := 0; i < iterations; i++ {
+				var key = NewIntKey(r.Intn(keys))
+				switch r.Intn(10) {
+				case 0, 1, 2:
+					c.Add(NewEntry(key, i, time.Duration(r.Intn(5))*time.Millisecond, Second(10)))
+				case 3, 4, 5:
+					if e := c.Get(key); e != nil {
+						e.Value()
+					}
+				case 6:
+					c.Remove(key)
+				case 7:
+					c.HouseCleaning()
+				case 8:
+					c.RemoveLruEntry()
+				default:
+					c.Keys()
+					c.Len()
+					c.IsFull()
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	for _, shard := range c.(*shardedCache[keyIdentity]).shards {
+		if shard.Len() > shard.Capacity() {
+			t.Errorf("Shard len = %d is greater than its capacity %d", shard.Len(), shard.Capacity())
+		}
+	}
+}
+
+// benchmarkParallelCache runs a read-heavy mixed workload from all the benchmark goroutines.
+func benchmarkParallelCache(b *testing.B, c Cache) {
+	const keys = 4096
+	var entryKeys = make([]EntryKey, keys)
+	for i := range entryKeys {
+		entryKeys[i] = NewIntKey(i)
+		c.Add(NewEntry(entryKeys[i], i, Second(60), Second(60)))
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var r = rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			var key = entryKeys[r.Intn(keys)]
+			if r.Intn(10) == 0 {
+				c.Add(NewEntry(key, 0, Second(60), Second(60)))
+			} else {
+				c.Get(key)
+			}
+		}
+	})
+}
+
+func BenchmarkSyncCache_Parallel(b *testing.B) {
+	benchmarkParallelCache(b, NewSyncCache(4096))
+}
+
+func BenchmarkShardedCache_Parallel(b *testing.B) {
+	for _, shards := range []uint32{4, 16, 64} {
+		b.Run(strconv.Itoa(int(shards))+"Shards", func(b *testing.B) {
+			benchmarkParallelCache(b, NewShardedCache(shards, 4096))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package LruCache
+
+import (
+	"container/list"
+	"sync/atomic"
+)
+
+// sieveNode is an entry of the SIEVE queue with its visited bit.
+type sieveNode struct {
+	cacheEntry Entry
+	visited    uint32
+}
+
+// sievePolicy is the SIEVE policy.
+// The entries are queued in insertion order, the newest at the front, and a hit only sets their visited bit.
+// The hand moves from the back to the front, clearing the visited bits, until it finds an entry not visited:
+// the victim, which stays under the hand. The hand wraps around to the back after the front.
+// The access only sets the visited bit with an atomic operation, so that it may run concurrently with itself.
+type sievePolicy struct {
+	queue *list.List
+	nodes map[keyIdentity]*list.Element
+	hand  *list.Element
+}
+
+// OnInsert adds the entry to the front of the queue, not visited.
+func (p *sievePolicy) OnInsert(cacheEntry Entry) {
+	p.nodes[keyOf(cacheEntry.Key())] = p.queue.PushFront(&sieveNode{cacheEntry: cacheEntry})
+}
+
+// OnAccess sets the visited bit of the entry.
+func (p *sievePolicy) OnAccess(cacheEntry Entry) {
+	if element, exists := p.nodes[keyOf(cacheEntry.Key())]; exists {
+		atomic.StoreUint32(&element.Value.(*sieveNode).visited, 1)
+	}
+}
+
+// concurrentAccess marks OnAccess as safe for concurrent use.
+func (p *sievePolicy) concurrentAccess() {}
+
+// OnRemove unlinks the entry, the hand moves to the next entry toward the front if it was on the entry.
+func (p *sievePolicy) OnRemove(cacheEntry Entry, _ EvictionReason) {
+	var key = keyOf(cacheEntry.Key())
+	var element, exists = p.nodes[key]
+	if !exists {
+		return
+	}
+	if p.hand == element {
+		p.hand = element.Prev()
+	}
+	p.queue.Remove(element)
+	delete(p.nodes, key)
+}
+
+// Victim moves the hand toward the front, clearing the visited bits, and returns the first entry not visited.
+func (p *sievePolicy) Victim(_ Entry) Entry {
+	if p.queue.Len() == 0 {
+		return nil
+	}
+	for {
+		if p.hand == nil {
+			p.hand = p.queue.Back()
+		}
+		var node = p.hand.Value.(*sieveNode)
+		if atomic.LoadUint32(&node.visited) == 0 {
+			return node.cacheEntry
+		}
+		atomic.StoreUint32(&node.visited, 0)
+		p.hand = p.hand.Prev()
+	}
+}
+
+// Reset forgets all the entries.
+func (p *sievePolicy) Reset() {
+	p.queue = list.New()
+	p.nodes = make(map[keyIdentity]*list.Element)
+	p.hand = nil
+}
+
+// newSievePolicy returns a new SIEVE eviction policy.
+func newSievePolicy(capacity uint32) EvictionPolicy {
+	return &sievePolicy{
+		queue: list.New(),
+		nodes: make(map[keyIdentity]*list.Element, capacity),
+	}
+}
+
+// WithSieveEviction makes the cache use the SIEVE policy: the entries are kept in insertion order,
+// a hit only sets a visited bit, and a hand moving from the oldest entries evicts the first entry not visited.
+// The caches returned by NewSyncCache and NewShardedCache record the hits under their read lock with this policy.
+func WithSieveEviction() CacheOption {
+	return WithEvictionPolicy(newSievePolicy)
+}
+
+// NewSieveCache returns a new cache able to store size entries, using the SIEVE policy.
+// The returned cache is not safe for concurrent use, use NewSyncCache with WithSieveEviction instead.
+func NewSieveCache(size uint32, options ...CacheOption) Cache {
+	return NewCache(size, append(options[:len(options):len(options)], WithSieveEviction())...)
+}
@@ -0,0 +1,117 @@
+package LruCache
+
+import (
+	"reflect"
+	"testing"
+)
+
+// sieveState returns the keys of the queue from the front, their visited bits, and the key under the hand or 0.
+func sieveState(p *sievePolicy) ([]int, []uint32, int) {
+	var keys = make([]int, 0, p.queue.Len())
+	var bits = make([]uint32, 0, p.queue.Len())
+	for e := p.queue.Front(); e != nil; e = e.Next() {
+		var node = e.Value.(*sieveNode)
+		keys = append(keys, node.cacheEntry.Value().(int))
+		bits = append(bits, node.visited)
+	}
+	var hand int
+	if p.hand != nil {
+		hand = p.hand.Value.(*sieveNode).cacheEntry.Value().(int)
+	}
+	return keys, bits, hand
+}
+
+func Test_sievePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity uint32
+		scenario func(c Cache)
+		keys     []int
+		bits     []uint32
+		hand     int
+	}{
+		{
+			name:     "A hit sets the visited bit without reordering",
+			capacity: 4,
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.GetWithoutAccessUpdate(NewIntKey(2))
+			},
+			keys: []int{4, 3, 2, 1},
+			bits: []uint32{0, 0, 0, 1},
+		},
+		{
+			name:     "The hand skips the visited entries and stays in place",
+			capacity: 4,
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(3))
+				c.Add(intEntry(5))
+				c.Add(intEntry(6))
+			},
+			keys: []int{6, 5, 3, 1},
+			bits: []uint32{0, 0, 0, 0},
+			hand: 5,
+		},
+		{
+			name:     "The hand wraps around",
+			capacity: 3,
+			scenario: func(c Cache) {
+				for k := 1; k <= 3; k++ {
+					c.Add(intEntry(k))
+					c.Get(NewIntKey(k))
+				}
+				c.Add(intEntry(4))
+			},
+			keys: []int{4, 3, 2},
+			bits: []uint32{0, 0, 0},
+			hand: 2,
+		},
+		{
+			name:     "Removing the entry under the hand moves the hand",
+			capacity: 4,
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(3))
+				c.Add(intEntry(5))
+				c.Remove(NewIntKey(3))
+			},
+			keys: []int{5, 4, 1},
+			bits: []uint32{0, 0, 0},
+			hand: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = newCache(tt.capacity, WithSieveEviction())
+			tt.scenario(c)
+			var keys, bits, hand = sieveState(c.policy.(*sievePolicy))
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("Queue = %v, want %v", keys, tt.keys)
+			}
+			if !reflect.DeepEqual(bits, tt.bits) {
+				t.Errorf("Visited bits = %v, want %v", bits, tt.bits)
+			}
+			if hand != tt.hand {
+				t.Errorf("Hand on %d, want %d", hand, tt.hand)
+			}
+		})
+	}
+}
+
+func BenchmarkSieveCache_Trace(b *testing.B) {
+	benchmarkTrace(b, func(size uint32) Cache { return NewSieveCache(size) })
+}
+
+func BenchmarkSieveSyncCache_Parallel(b *testing.B) {
+	benchmarkParallelCache(b, NewSyncCache(4096, WithSieveEviction()))
+}
@@ -0,0 +1,126 @@
+package LruCache
+
+import "container/list"
+
+// DefaultSlruProtectedRatio is the share of the cache capacity given to the protected segment by NewSlruCache.
+const DefaultSlruProtectedRatio = 0.8
+
+// slruNode links an entry to its segment list.
+type slruNode struct {
+	element   *list.Element
+	protected bool
+}
+
+// slruPolicy is the segmented LRU policy.
+// The new entries land in the probationary segment, a hit promotes them to the protected segment,
+// and the least recently used entries of the protected overflow are demoted back to the probationary segment.
+// The victim is the least recently used entry of the probationary segment, or of the protected one when empty.
+type slruPolicy struct {
+	probation         *list.List
+	protected         *list.List
+	nodes             map[keyIdentity]*slruNode
+	protectedRatio    float64
+	protectedCapacity int
+}
+
+// OnInsert adds the entry to the front of the probationary segment.
+func (p *slruPolicy) OnInsert(cacheEntry Entry) {
+	p.nodes[keyOf(cacheEntry.Key())] = &slruNode{element: p.probation.PushFront(cacheEntry)}
+}
+
+// OnAccess moves the entry to the front of the protected segment.
+func (p *slruPolicy) OnAccess(cacheEntry Entry) {
+	var node, exists = p.nodes[keyOf(cacheEntry.Key())]
+	if !exists {
+		return
+	}
+	if node.protected {
+		p.protected.MoveToFront(node.element)
+	} else {
+		p.probation.Remove(node.element)
+		node.element = p.protected.PushFront(cacheEntry)
+		node.protected = true
+		p.demote()
+	}
+}
+
+// demote moves the protected overflow to the front of the probationary segment.
+func (p *slruPolicy) demote() {
+	for p.protected.Len() > p.protectedCapacity {
+		var cacheEntry = p.protected.Remove(p.protected.Back()).(Entry)
+		var node = p.nodes[keyOf(cacheEntry.Key())]
+		node.element = p.probation.PushFront(cacheEntry)
+		node.protected = false
+	}
+}
+
+// OnRemove forgets the entry.
+func (p *slruPolicy) OnRemove(cacheEntry Entry, _ EvictionReason) {
+	var key = keyOf(cacheEntry.Key())
+	if node, exists := p.nodes[key]; exists {
+		if node.protected {
+			p.protected.Remove(node.element)
+		} else {
+			p.probation.Remove(node.element)
+		}
+		delete(p.nodes, key)
+	}
+}
+
+// Victim returns the least recently used entry of the probationary segment, or of the protected one.
+func (p *slruPolicy) Victim(_ Entry) Entry {
+	if p.probation.Len() > 0 {
+		return p.probation.Back().Value.(Entry)
+	} else if p.protected.Len() > 0 {
+		return p.protected.Back().Value.(Entry)
+	} else {
+		return nil
+	}
+}
+
+// Resize updates the protected segment capacity, demoting its overflow.
+func (p *slruPolicy) Resize(capacity uint32) {
+	p.protectedCapacity = int(float64(capacity) * p.protectedRatio)
+	p.demote()
+}
+
+// Reset forgets all the entries.
+func (p *slruPolicy) Reset() {
+	p.probation = list.New()
+	p.protected = list.New()
+	p.nodes = make(map[keyIdentity]*slruNode)
+}
+
+// newSlruPolicy returns a new SLRU policy giving the protected ratio of the capacity to the protected segment.
+// The ratio is capped between 0 and 1.
+func newSlruPolicy(capacity uint32, protectedRatio float64) *slruPolicy {
+	if protectedRatio < 0 {
+		protectedRatio = 0
+	} else if protectedRatio > 1 {
+		protectedRatio = 1
+	}
+	var np = &slruPolicy{
+		probation:      list.New(),
+		protected:      list.New(),
+		nodes:          make(map[keyIdentity]*slruNode, capacity),
+		protectedRatio: protectedRatio,
+	}
+	np.Resize(capacity)
+	return np
+}
+
+// WithSlruEviction makes the cache use the segmented LRU policy: the new entries land in a probationary segment,
+// and are promoted to a protected segment on their first hit, which is the second lookup of a key loaded on a miss.
+// The protected ratio is the share of the capacity given to the protected segment, between 0 and 1.
+// The protected overflow is demoted back to the probationary segment, the victims are taken from the probationary segment first.
+func WithSlruEviction(protectedRatio float64) CacheOption {
+	return WithEvictionPolicy(func(capacity uint32) EvictionPolicy {
+		return newSlruPolicy(capacity, protectedRatio)
+	})
+}
+
+// NewSlruCache returns a new cache able to store size entries, using the segmented LRU policy with DefaultSlruProtectedRatio.
+// The returned cache is not safe for concurrent use, use NewSyncCache with WithSlruEviction instead.
+func NewSlruCache(size uint32, options ...CacheOption) Cache {
+	return NewCache(size, append(options[:len(options):len(options)], WithSlruEviction(DefaultSlruProtectedRatio))...)
+}
@@ -0,0 +1,136 @@
+package LruCache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_slruPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		scenario  func(c Cache)
+		probation []int
+		protected []int
+	}{
+		{
+			name: "New entries land in the probationary segment",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+			},
+			probation: []int{2, 1}, protected: []int{},
+		},
+		{
+			name: "A hit promotes the entry",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Get(NewIntKey(1))
+				c.GetWithoutAccessUpdate(NewIntKey(2))
+			},
+			probation: []int{2}, protected: []int{1},
+		},
+		{
+			name: "The protected overflow is demoted to the front of the probationary segment",
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(2))
+				c.Get(NewIntKey(3))
+			},
+			probation: []int{1, 4}, protected: []int{3, 2},
+		},
+		{
+			name: "The victims are taken from the probationary segment first",
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(2))
+				c.Add(intEntry(5))
+				c.Add(intEntry(6))
+				c.Add(intEntry(7))
+			},
+			probation: []int{7, 6}, protected: []int{2, 1},
+		},
+		{
+			name: "The protected segment is evicted once the probationary segment is empty",
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(2))
+				c.RemoveLruEntry()
+			},
+			probation: []int{}, protected: []int{2},
+		},
+		{
+			name: "Downsizing demotes the protected overflow",
+			scenario: func(c Cache) {
+				for k := 1; k <= 4; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+				c.Get(NewIntKey(2))
+				c.Resize(2)
+			},
+			probation: []int{1}, protected: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = newCache(4, WithSlruEviction(0.5))
+			tt.scenario(c)
+			var p = c.policy.(*slruPolicy)
+			if got := listKeys(p.probation); !reflect.DeepEqual(got, tt.probation) {
+				t.Errorf("Probation segment = %v, want %v", got, tt.probation)
+			}
+			if got := listKeys(p.protected); !reflect.DeepEqual(got, tt.protected) {
+				t.Errorf("Protected segment = %v, want %v", got, tt.protected)
+			}
+		})
+	}
+}
+
+func Test_newSlruPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		capacity       uint32
+		protectedRatio float64
+		want           int
+	}{
+		{name: "Default ratio", capacity: 10, protectedRatio: DefaultSlruProtectedRatio, want: 8},
+		{name: "Half", capacity: 9, protectedRatio: 0.5, want: 4},
+		{name: "Negative ratio", capacity: 10, protectedRatio: -1, want: 0},
+		{name: "Ratio above 1", capacity: 10, protectedRatio: 2, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newSlruPolicy(tt.capacity, tt.protectedRatio).protectedCapacity; got != tt.want {
+				t.Errorf("Protected capacity = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlruCache_OneTimeLookups(t *testing.T) {
+	var c = NewSlruCache(10)
+	for k := 1; k <= 4; k++ {
+		c.Add(intEntry(k))
+		c.Get(NewIntKey(k))
+	}
+	// A burst of one-time lookups loading their value on a miss
+	for k := 100; k < 200; k++ {
+		if c.Get(NewIntKey(k)) == nil {
+			c.Add(intEntry(k))
+		}
+	}
+	for k := 1; k <= 4; k++ {
+		if !c.Contains(NewIntKey(k)) {
+			t.Errorf("The frequently read entry %d was evicted", k)
+		}
+	}
+}
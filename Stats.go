@@ -0,0 +1,98 @@
+package LruCache
+
+import "sync/atomic"
+
+// Stats are the cache statistics since its creation or the last ResetStats call.
+type Stats struct {
+	// Hits is the number of lookups which found a valid entry.
+	Hits uint64
+	// Misses is the number of lookups which didn't find a valid entry, expired entries included.
+	Misses uint64
+	// ExpiredOnRead is the number of lookups which found an expired entry and removed it.
+	ExpiredOnRead uint64
+	// Inserts is the number of entries added for a new key.
+	Inserts uint64
+	// Replacements is the number of entries added in place of an entry having the same key.
+	Replacements uint64
+	// CapacityEvictions is the number of entries evicted by Add because the cache was full.
+	CapacityEvictions uint64
+	// HouseCleaningRemovals is the number of expired entries removed by HouseCleaning.
+	HouseCleaningRemovals uint64
+	// ResizeEvictions is the number of entries evicted by Resize.
+	ResizeEvictions uint64
+	// Flushes is the number of Flush calls.
+	Flushes uint64
+	// FlushedEntries is the number of entries removed by Flush.
+	FlushedEntries uint64
+}
+
+// HitRatio returns the ratio of the lookups which found a valid entry, or 0 without lookup.
+func (s Stats) HitRatio() float64 {
+	var lookups = s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+// add returns the sum of the statistics s and o.
+func (s Stats) add(o Stats) Stats {
+	return Stats{
+		Hits:                  s.Hits + o.Hits,
+		Misses:                s.Misses + o.Misses,
+		ExpiredOnRead:         s.ExpiredOnRead + o.ExpiredOnRead,
+		Inserts:               s.Inserts + o.Inserts,
+		Replacements:          s.Replacements + o.Replacements,
+		CapacityEvictions:     s.CapacityEvictions + o.CapacityEvictions,
+		HouseCleaningRemovals: s.HouseCleaningRemovals + o.HouseCleaningRemovals,
+		ResizeEvictions:       s.ResizeEvictions + o.ResizeEvictions,
+		Flushes:               s.Flushes + o.Flushes,
+		FlushedEntries:        s.FlushedEntries + o.FlushedEntries,
+	}
+}
+
+// cacheStats are the cache counters, they are updated and read with atomic operations
+// so that the statistics can be read without holding the cache lock.
+type cacheStats struct {
+	hits                  uint64
+	misses                uint64
+	expiredOnRead         uint64
+	inserts               uint64
+	replacements          uint64
+	capacityEvictions     uint64
+	houseCleaningRemovals uint64
+	resizeEvictions       uint64
+	flushes               uint64
+	flushedEntries        uint64
+}
+
+// snapshot returns the current value of the counters.
+func (cs *cacheStats) snapshot() Stats {
+	return Stats{
+		Hits:                  atomic.LoadUint64(&cs.hits),
+		Misses:                atomic.LoadUint64(&cs.misses),
+		ExpiredOnRead:         atomic.LoadUint64(&cs.expiredOnRead),
+		Inserts:               atomic.LoadUint64(&cs.inserts),
+		Replacements:          atomic.LoadUint64(&cs.replacements),
+		CapacityEvictions:     atomic.LoadUint64(&cs.capacityEvictions),
+		HouseCleaningRemovals: atomic.LoadUint64(&cs.houseCleaningRemovals),
+		ResizeEvictions:       atomic.LoadUint64(&cs.resizeEvictions),
+		Flushes:               atomic.LoadUint64(&cs.flushes),
+		FlushedEntries:        atomic.LoadUint64(&cs.flushedEntries),
+	}
+}
+
+// reset sets all the counters to zero.
+func (cs *cacheStats) reset() {
+	for _, counter := range []*uint64{
+		&cs.hits, &cs.misses, &cs.expiredOnRead, &cs.inserts, &cs.replacements, &cs.capacityEvictions,
+		&cs.houseCleaningRemovals, &cs.resizeEvictions, &cs.flushes, &cs.flushedEntries,
+	} {
+		atomic.StoreUint64(counter, 0)
+	}
+}
+
+// increment adds n to the counter.
+func increment(counter *uint64, n uint64) {
+	atomic.AddUint64(counter, n)
+}
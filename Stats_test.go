@@ -0,0 +1,108 @@
+package LruCache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mmaFR/LruCache/clocktest"
+)
+
+func TestCache_Stats(t *testing.T) {
+	var constructors = map[string]func(size uint32, options ...CacheOption) Cache{
+		"cache":     NewCache,
+		"syncCache": NewSyncCache,
+		"shardedCache": func(size uint32, options ...CacheOption) Cache {
+			return NewShardedCache(1, size, options...)
+		},
+	}
+	for name, constructor := range constructors {
+		t.Run(name, func(t *testing.T) {
+			var clock = clocktest.NewFakeClock(time.Now())
+			var c = constructor(3, WithClock(clock))
+			var newEntry = func(key string, ttl time.Duration) Entry {
+				return NewEntry(NewStringKey(key), key+" entry", ttl, Second(3600), WithEntryClock(clock))
+			}
+			c.Add(newEntry("A", Second(10)))
+			c.Add(newEntry("B", Second(10)))
+			c.Add(newEntry("C", Second(60)))
+			c.Add(newEntry("A", Second(10)))
+			c.Add(newEntry("D", Second(60)))
+			c.Get(NewStringKey("A"))
+			c.Get(NewStringKey("B"))
+			c.GetWithoutAccessUpdate(NewStringKey("C"))
+			clock.Advance(Second(20))
+			c.Get(NewStringKey("A"))
+			c.HouseCleaning()
+			c.Add(newEntry("E", Second(60)))
+			c.Resize(1)
+			c.Flush()
+
+			var want = Stats{
+				Hits:                  2,
+				Misses:                2,
+				ExpiredOnRead:         1,
+				Inserts:               5,
+				Replacements:          1,
+				CapacityEvictions:     1,
+				HouseCleaningRemovals: 0,
+				ResizeEvictions:       2,
+				Flushes:               1,
+				FlushedEntries:        1,
+			}
+			if got := c.Stats(); got != want {
+				t.Errorf("Stats() = %+v, want %+v", got, want)
+			}
+			if got := c.Stats().HitRatio(); got != 0.5 {
+				t.Errorf("HitRatio() = %v, want 0.5", got)
+			}
+			c.ResetStats()
+			if got := c.Stats(); got != (Stats{}) {
+				t.Errorf("Stats() = %+v after ResetStats(), want zero", got)
+			}
+		})
+	}
+}
+
+func TestCache_Stats_HouseCleaning(t *testing.T) {
+	var clock = clocktest.NewFakeClock(time.Now())
+	var c = NewCache(8, WithClock(clock))
+	c.Add(NewEntry(NewStringKey("A"), "A entry", Second(10), Second(3600), WithEntryClock(clock)))
+	c.Add(NewEntry(NewStringKey("B"), "B entry", Second(10), Second(3600), WithEntryClock(clock)))
+	c.Add(NewEntry(NewStringKey("C"), "C entry", Second(60), Second(3600), WithEntryClock(clock)))
+	clock.Advance(Second(20))
+	c.HouseCleaning()
+	if got := c.Stats().HouseCleaningRemovals; got != 2 {
+		t.Errorf("HouseCleaningRemovals = %d, want 2", got)
+	}
+}
+
+func TestStats_HitRatio(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats Stats
+		want  float64
+	}{
+		{
+			name:  "No lookup",
+			stats: Stats{},
+			want:  0,
+		},
+		{
+			name:  "Only hits",
+			stats: Stats{Hits: 4},
+			want:  1,
+		},
+		{
+			name:  "Hits and misses",
+			stats: Stats{Hits: 3, Misses: 1},
+			want:  0.75,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.HitRatio(); got != tt.want {
+				t.Errorf("HitRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package LruCache
+
+import (
+	"context"
+	"sync"
+)
+
+// syncCache is a cache object safe for concurrent use.
+// Every operation which may update the entries map or the eviction policy, including Get and
+// GetWithoutAccessUpdate which remove expired entries, holds the write lock.
+// With an eviction policy whose access is safe for concurrent use, like CLOCK, the Get hits hold the read lock only.
+type syncCache[K comparable] struct {
+	mu    sync.RWMutex
+	cache *cache[K]
+}
+
+// Add adds a new entry in the cache, or replaces the entry having the same key.
+// It returns what happened and the displaced entry: the replaced entry, the evicted entry, or nil.
+func (sc *syncCache[K]) Add(cacheEntry Entry) (AddResult, Entry) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.Add(cacheEntry)
+}
+
+// Get returns the entry corresponding to the requested key. It returns nil if the entry doesn't exist or expired.
+// It updates the entry last access time and records the hit in the eviction policy,
+// the default policy moves the entry to the front of the LRU list.
+// The hits hold the read lock only if the eviction policy supports concurrent access, see WithClockEviction.
+func (sc *syncCache[K]) Get(key EntryKey) Entry {
+	return sc.getKey(sc.cache.identify(key))
+}
+
+// getKey is Get for the key identity.
+func (sc *syncCache[K]) getKey(key K) Entry {
+	if _, ok := sc.cache.policy.(concurrentAccessPolicy); ok {
+		sc.mu.RLock()
+		var cacheEntry = sc.cache.getShared(key)
+		sc.mu.RUnlock()
+		if cacheEntry != nil {
+			return cacheEntry
+		}
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.getKey(key)
+}
+
+// GetWithoutAccessUpdate returns the entry corresponding to the requested key.
+// It returns true if the entry exists.
+// It doesn't the update the entry last access time, and doesn't move the entry in the LRU list.
+func (sc *syncCache[K]) GetWithoutAccessUpdate(key EntryKey) Entry {
+	return sc.getKeyWithoutAccessUpdate(sc.cache.identify(key))
+}
+
+// getKeyWithoutAccessUpdate is GetWithoutAccessUpdate for the key identity.
+func (sc *syncCache[K]) getKeyWithoutAccessUpdate(key K) Entry {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.getKeyWithoutAccessUpdate(key)
+}
+
+// GetLruEntry returns the entry the eviction policy would evict next, the oldest cache entry by default.
+// It doesn't the update the entry last access time.
+// It holds the write lock, choosing the victim may update the eviction policy.
+func (sc *syncCache[K]) GetLruEntry() Entry {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.GetLruEntry()
+}
+
+// Contains returns true if the cache contains an entry for the requested key.
+func (sc *syncCache[K]) Contains(key EntryKey) bool {
+	return sc.containsKey(sc.cache.identify(key))
+}
+
+// containsKey is Contains for the key identity.
+func (sc *syncCache[K]) containsKey(key K) bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.cache.containsKey(key)
+}
+
+// Remove removes the cache entry corresponding the requested key.
+// It returns true if the entry exists, and the removed entry.
+func (sc *syncCache[K]) Remove(key EntryKey) (bool, Entry) {
+	return sc.removeKey(sc.cache.identify(key))
+}
+
+// removeKey is Remove for the key identity.
+func (sc *syncCache[K]) removeKey(key K) (bool, Entry) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.removeKey(key)
+}
+
+// RemoveLruEntry removes the entry the eviction policy would evict next, the least recently used one by default, and returns it.
+func (sc *syncCache[K]) RemoveLruEntry() Entry {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.RemoveLruEntry()
+}
+
+// Keys returns the list of cache entries keys.
+func (sc *syncCache[K]) Keys() []EntryKey {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.cache.Keys()
+}
+
+// Len returns the number of entries present in the cache.
+func (sc *syncCache[K]) Len() uint32 {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.cache.Len()
+}
+
+// Capacity returns the cache capacity.
+func (sc *syncCache[K]) Capacity() uint32 {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.cache.Capacity()
+}
+
+// Flush clears the cache and returns the number of entries flushed.
+func (sc *syncCache[K]) Flush() uint32 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.Flush()
+}
+
+// Resize updates the cache capacity and returns the number of cache entries flushed during the downsizing and the slice of flushed entries.
+func (sc *syncCache[K]) Resize(size uint32) (uint32, []Entry) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.Resize(size)
+}
+
+// HouseCleaning triggers the cache cleaning and removes the entries expired.
+// It returns the number of flushed entries and the slice of them.
+func (sc *syncCache[K]) HouseCleaning() (uint32, []Entry) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.cache.HouseCleaning()
+}
+
+// IsFull returns true if the cache reaches its maximum capacity
+func (sc *syncCache[K]) IsFull() bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.cache.IsFull()
+}
+
+// Stats returns the cache statistics.
+// The counters are atomic, the lock is not needed.
+func (sc *syncCache[K]) Stats() Stats {
+	return sc.cache.Stats()
+}
+
+// ResetStats sets the cache statistics to zero.
+func (sc *syncCache[K]) ResetStats() {
+	sc.cache.ResetStats()
+}
+
+// GetOrLoad returns the entry corresponding to the requested key like Get.
+// On a miss, it calls the loader, adds a new entry holding the loaded value and returns it.
+// The loader error is returned as is and nothing is added to the cache.
+// The loader is called without holding the lock, concurrent misses on the same key may call it several times.
+func (sc *syncCache[K]) GetOrLoad(key EntryKey, loader Loader, options ...LoadOption) (Entry, error) {
+	return sc.GetOrLoadContext(context.Background(), key, contextLoader(loader), options...)
+}
+
+// GetOrLoadContext is like GetOrLoad, the context is given to the loader.
+// The loader is not called if the context is already done.
+func (sc *syncCache[K]) GetOrLoadContext(ctx context.Context, key EntryKey, loader ContextLoader, options ...LoadOption) (Entry, error) {
+	return getOrLoad(ctx, sc, sc.cache.loadDefaults, sc.cache.clock, key, loader, options)
+}
+
+// loadEntry loads the value of the requested key without holding the lock, adds the new entry in the cache and returns it.
+func (sc *syncCache[K]) loadEntry(ctx context.Context, key EntryKey, loader ContextLoader, options []LoadOption) (Entry, error) {
+	return loadEntry(ctx, sc, sc.cache.loadDefaults, sc.cache.clock, key, loader, options)
+}
+
+// reindex updates the expiry deadline of the cache entry after a TTL or max age update.
+// It is called by the entries outside the cache, so it takes the lock.
+func (sc *syncCache[K]) reindex(cacheEntry Entry) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.cache.reindex(cacheEntry)
+}
+
+// NewSyncCache returns a new cache able to store size entries and safe for concurrent use.
+// The eviction callback is called while the cache lock is held, it must not call the cache.
+func NewSyncCache(size uint32, options ...CacheOption) Cache {
+	return newSyncCache(newCache(size, options...))
+}
+
+// newSyncCache returns a new cache safe for concurrent use wrapping c.
+func newSyncCache[K comparable](c *cache[K]) *syncCache[K] {
+	var nc = new(syncCache[K])
+	nc.cache = c
+	nc.cache.lifetimeHook = nc.reindex
+	return nc
+}
@@ -0,0 +1,133 @@
+package LruCache
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewSyncCache(t *testing.T) {
+	tests := []struct {
+		name string
+		size uint32
+		want uint32
+	}{
+		{
+			name: "Create sync cache",
+			size: 128,
+			want: 128,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewSyncCache(tt.size)
+			if c := got.Capacity(); c != tt.want {
+				t.Errorf("New sync cache capacity = %d, want %d", c, tt.want)
+			}
+			if got.(*syncCache[keyIdentity]).cache.capacity != tt.want {
+				t.Errorf("Inner cache capacity = %d, want %d", got.(*syncCache[keyIdentity]).cache.capacity, tt.want)
+			}
+		})
+	}
+}
+
+func Test_syncCache_Operations(t *testing.T) {
+	var c = NewSyncCache(2)
+	var a = NewEntry(NewStringKey("A"), "A entry", Second(10), Second(15))
+	var b = NewEntry(NewStringKey("B"), "B entry", Second(10), Second(15))
+	var d = NewEntry(NewStringKey("D"), "D entry", Second(10), Second(15))
+
+	if got, _ := c.Add(a); got != EntryInserted {
+		t.Errorf("Add() = %s, want %s", got, EntryInserted)
+	}
+	c.Add(b)
+	if got := c.Get(NewStringKey("A")); got != a {
+		t.Errorf("Get() = %v, want %v", got, a)
+	}
+	if got, got1 := c.Add(d); got != LruEntryEvicted || got1 != b {
+		t.Errorf("Add() = %s, %v, want %s, %v", got, got1, LruEntryEvicted, b)
+	}
+	if !c.IsFull() {
+		t.Error("The cache should be full")
+	}
+	if got := c.GetLruEntry(); got != a {
+		t.Errorf("GetLruEntry() = %v, want %v", got, a)
+	}
+	if got, got1 := c.Remove(NewStringKey("A")); !got || got1 != a {
+		t.Errorf("Remove() = %t, %v, want true, %v", got, got1, a)
+	}
+	if got := c.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+	if got, _ := c.Resize(4); got != 0 {
+		t.Errorf("Resize() = %d, want 0", got)
+	}
+	if got := c.Flush(); got != 1 {
+		t.Errorf("Flush() = %d, want 1", got)
+	}
+}
+
+func Test_syncCache_Stress(t *testing.T) {
+	const goroutines = 16
+	const iterations = 2000
+	const keys = 64
+
+	var c = NewSyncCache(32)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			var r = rand.New(rand.NewSource(seed))
+			for i := 0; i < iterations; i++ {
+				var key = NewStringKey(strconv.Itoa(r.Intn(keys)))
+				switch r.Intn(12) {
+				case 0, 1, 2:
+					c.Add(NewEntry(key, i, time.Duration(r.Intn(5))*time.Millisecond, Second(10)))
+				case 3, 4, 5:
+					if e := c.Get(key); e != nil {
+						e.Value()
+						e.GetDurationBeforeFlush()
+					}
+				case 6:
+					c.GetWithoutAccessUpdate(key)
+				case 7:
+					c.Remove(key)
+				case 8:
+					c.HouseCleaning()
+				case 9:
+					c.Resize(uint32(16 + r.Intn(32)))
+				case 10:
+					if r.Intn(50) == 0 {
+						c.Flush()
+					} else {
+						c.RemoveLruEntry()
+					}
+				default:
+					c.Contains(key)
+					c.Keys()
+					c.GetLruEntry()
+					c.IsFull()
+				}
+				if lenAboveCapacity(c.(*syncCache[keyIdentity])) {
+					t.Error("The cache holds more entries than its capacity")
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	var sc = c.(*syncCache[keyIdentity])
+	if m, l := len(sc.cache.cacheMap), sc.cache.policy.(*lruPolicy).list.Len(); m != l {
+		t.Errorf("Map len = %d and LRU list len = %d are not consistent", m, l)
+	}
+}
+
+// lenAboveCapacity returns true if the cache holds more entries than its capacity, under a single lock.
+func lenAboveCapacity(sc *syncCache[keyIdentity]) bool {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.cache.Len() > sc.cache.Capacity()
+}
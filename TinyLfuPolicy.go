@@ -0,0 +1,142 @@
+package LruCache
+
+import (
+	"container/list"
+	"hash/maphash"
+)
+
+// tinyLfuPolicy is the W-TinyLFU policy.
+// The new entries land in a small window LRU, and leave it for the probationary segment of the main segmented LRU.
+// When the cache is full, the entry leaving the window competes with the victim of the main segments:
+// the one having the lowest frequency estimated by the count-min sketch is evicted.
+type tinyLfuPolicy struct {
+	window         *list.List
+	windowNodes    map[keyIdentity]*list.Element
+	main           *slruPolicy
+	sketch         *countMinSketch
+	seed           maphash.Seed
+	capacity       uint32
+	windowCapacity int
+}
+
+// hash returns the hash of the entry key identity in the sketch.
+func (p *tinyLfuPolicy) hash(cacheEntry Entry) uint32 {
+	return uint32(maphash.Comparable(p.seed, keyOf(cacheEntry.Key())))
+}
+
+// OnInsert adds the entry to the window, the window overflow moves to the main segments.
+func (p *tinyLfuPolicy) OnInsert(cacheEntry Entry) {
+	p.sketch.increment(p.hash(cacheEntry))
+	p.windowNodes[keyOf(cacheEntry.Key())] = p.window.PushFront(cacheEntry)
+	p.shrinkWindow()
+}
+
+// shrinkWindow moves the window overflow to the main segments.
+func (p *tinyLfuPolicy) shrinkWindow() {
+	for p.window.Len() > p.windowCapacity {
+		var cacheEntry = p.window.Remove(p.window.Back()).(Entry)
+		delete(p.windowNodes, keyOf(cacheEntry.Key()))
+		p.main.OnInsert(cacheEntry)
+	}
+}
+
+// OnAccess moves the entry to the front of the window, or records the hit in the main segments.
+func (p *tinyLfuPolicy) OnAccess(cacheEntry Entry) {
+	p.sketch.increment(p.hash(cacheEntry))
+	if element, exists := p.windowNodes[keyOf(cacheEntry.Key())]; exists {
+		p.window.MoveToFront(element)
+	} else {
+		p.main.OnAccess(cacheEntry)
+	}
+}
+
+// OnRemove forgets the entry, the sketch keeps its frequency.
+func (p *tinyLfuPolicy) OnRemove(cacheEntry Entry, reason EvictionReason) {
+	var key = keyOf(cacheEntry.Key())
+	if element, exists := p.windowNodes[key]; exists {
+		p.window.Remove(element)
+		delete(p.windowNodes, key)
+	} else {
+		p.main.OnRemove(cacheEntry, reason)
+	}
+}
+
+// Victim returns the loser of the competition between the entry the candidate pushes out of the window
+// and the victim of the main segments, the window entry loses the ties.
+// The main victim is returned while the window isn't full.
+func (p *tinyLfuPolicy) Victim(_ Entry) Entry {
+	var mainVictim = p.main.Victim(nil)
+	if p.window.Len() == 0 || (p.window.Len() < p.windowCapacity && mainVictim != nil) {
+		return mainVictim
+	}
+	var windowVictim = p.window.Back().Value.(Entry)
+	if mainVictim == nil {
+		return windowVictim
+	}
+	if p.sketch.estimate(p.hash(windowVictim)) > p.sketch.estimate(p.hash(mainVictim)) {
+		return mainVictim
+	} else {
+		return windowVictim
+	}
+}
+
+// Resize updates the window and main segments capacity, the sketch is rebuilt if its size changes.
+func (p *tinyLfuPolicy) Resize(capacity uint32) {
+	p.capacity = capacity
+	var mainCapacity uint32
+	p.windowCapacity, mainCapacity = tinyLfuCapacities(capacity)
+	p.main.Resize(mainCapacity)
+	if countMinSketchWidth(capacity)-1 != p.sketch.mask {
+		p.sketch = newCountMinSketch(capacity)
+	}
+	p.shrinkWindow()
+}
+
+// Reset forgets all the entries and their frequency.
+func (p *tinyLfuPolicy) Reset() {
+	p.window = list.New()
+	p.windowNodes = make(map[keyIdentity]*list.Element)
+	p.main.Reset()
+	p.sketch = newCountMinSketch(p.capacity)
+}
+
+// tinyLfuCapacities returns the window capacity, 1% of the cache capacity and at least 1,
+// and the main segments capacity.
+func tinyLfuCapacities(capacity uint32) (int, uint32) {
+	var window = capacity / 100
+	if window < 1 {
+		window = 1
+	}
+	if capacity < window {
+		return int(window), 0
+	}
+	return int(window), capacity - window
+}
+
+// newTinyLfuPolicy returns a new W-TinyLFU eviction policy.
+func newTinyLfuPolicy(capacity uint32) EvictionPolicy {
+	var windowCapacity, mainCapacity = tinyLfuCapacities(capacity)
+	var np = &tinyLfuPolicy{
+		window:         list.New(),
+		windowNodes:    make(map[keyIdentity]*list.Element),
+		main:           newSlruPolicy(mainCapacity, DefaultSlruProtectedRatio),
+		sketch:         newCountMinSketch(capacity),
+		seed:           maphash.MakeSeed(),
+		capacity:       capacity,
+		windowCapacity: windowCapacity,
+	}
+	return np
+}
+
+// WithTinyLfuEviction makes the cache use the W-TinyLFU policy: the new entries go through a small LRU window,
+// then are admitted in a segmented LRU only if they are estimated to be accessed more often than the entry they replace.
+// The access frequencies are estimated with a count-min sketch, aged periodically.
+func WithTinyLfuEviction() CacheOption {
+	return WithEvictionPolicy(newTinyLfuPolicy)
+}
+
+// NewTinyLfuCache returns a new cache able to store size entries, using the W-TinyLFU policy.
+// The returned cache is not safe for concurrent use, use NewSyncCache with WithTinyLfuEviction instead.
+func NewTinyLfuCache(size uint32, options ...CacheOption) Cache {
+	return NewCache(size, append(options[:len(options):len(options)], WithTinyLfuEviction())...)
+}
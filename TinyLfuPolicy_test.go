@@ -0,0 +1,155 @@
+package LruCache
+
+import (
+	"container/list"
+	"reflect"
+	"testing"
+)
+
+func Test_tinyLfuPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		scenario  func(c Cache)
+		window    []int
+		probation []int
+		protected []int
+	}{
+		{
+			name: "The window overflow moves to the probationary segment",
+			scenario: func(c Cache) {
+				for k := 1; k <= 3; k++ {
+					c.Add(intEntry(k))
+				}
+			},
+			window: []int{3}, probation: []int{2, 1}, protected: []int{},
+		},
+		{
+			name: "A probationary hit promotes the entry",
+			scenario: func(c Cache) {
+				for k := 1; k <= 3; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+			},
+			window: []int{3}, probation: []int{2}, protected: []int{1},
+		},
+		{
+			name: "The protected overflow is demoted",
+			scenario: func(c Cache) {
+				for k := 1; k <= 6; k++ {
+					c.Add(intEntry(k))
+				}
+				for k := 1; k <= 5; k++ {
+					c.Get(NewIntKey(k))
+				}
+			},
+			window: []int{6}, probation: []int{1}, protected: []int{5, 4, 3, 2},
+		},
+		{
+			name: "A one-hit candidate isn't admitted over a frequent victim",
+			scenario: func(c Cache) {
+				for k := 1; k <= 6; k++ {
+					c.Add(intEntry(k))
+					c.Get(NewIntKey(k))
+					c.Get(NewIntKey(k))
+				}
+				c.Add(intEntry(7))
+				c.Add(intEntry(8))
+			},
+			window: []int{8}, probation: []int{5, 4, 3, 2, 1}, protected: []int{},
+		},
+		{
+			name: "A frequent candidate is admitted over a one-hit victim",
+			scenario: func(c Cache) {
+				for k := 1; k <= 6; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Remove(NewIntKey(6))
+				c.Add(intEntry(6))
+				c.Get(NewIntKey(6))
+				c.Add(intEntry(7))
+			},
+			window: []int{7}, probation: []int{6, 5, 4, 3, 2}, protected: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = newCache(6, WithTinyLfuEviction())
+			tt.scenario(c)
+			var p = c.policy.(*tinyLfuPolicy)
+			for _, s := range []struct {
+				name string
+				list *list.List
+				want []int
+			}{{"Window", p.window, tt.window}, {"Probation", p.main.probation, tt.probation}, {"Protected", p.main.protected, tt.protected}} {
+				if got := listKeys(s.list); !reflect.DeepEqual(got, s.want) {
+					t.Errorf("%s segment = %v, want %v", s.name, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_tinyLfuCapacities(t *testing.T) {
+	tests := []struct {
+		name          string
+		capacity      uint32
+		wantWindow    int
+		wantMain      uint32
+		wantProtected int
+	}{
+		{name: "Empty cache", capacity: 0, wantWindow: 1, wantMain: 0, wantProtected: 0},
+		{name: "Tiny cache", capacity: 1, wantWindow: 1, wantMain: 0, wantProtected: 0},
+		{name: "Small cache", capacity: 6, wantWindow: 1, wantMain: 5, wantProtected: 4},
+		{name: "Large cache", capacity: 10000, wantWindow: 100, wantMain: 9900, wantProtected: 7920},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if window, main := tinyLfuCapacities(tt.capacity); window != tt.wantWindow || main != tt.wantMain {
+				t.Errorf("tinyLfuCapacities() = %d, %d, want %d, %d", window, main, tt.wantWindow, tt.wantMain)
+			}
+			var p = newTinyLfuPolicy(tt.capacity).(*tinyLfuPolicy)
+			if p.main.protectedCapacity != tt.wantProtected {
+				t.Errorf("Protected capacity = %d, want %d", p.main.protectedCapacity, tt.wantProtected)
+			}
+		})
+	}
+}
+
+func Test_tinyLfuPolicy_Resize(t *testing.T) {
+	tests := []struct {
+		name        string
+		capacity    uint32
+		keepsSketch bool
+	}{
+		{name: "Same sketch width", capacity: 1000, keepsSketch: true},
+		{name: "Minimum sketch width", capacity: 8, keepsSketch: false},
+		{name: "Larger sketch width", capacity: 4096, keepsSketch: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p = newTinyLfuPolicy(1024).(*tinyLfuPolicy)
+			var sketch = p.sketch
+			p.Resize(tt.capacity)
+			if kept := p.sketch == sketch; kept != tt.keepsSketch {
+				t.Errorf("Sketch kept = %v, want %v", kept, tt.keepsSketch)
+			}
+			if want := countMinSketchWidth(tt.capacity) - 1; p.sketch.mask != want {
+				t.Errorf("Sketch mask = %d, want %d", p.sketch.mask, want)
+			}
+			if tt.keepsSketch {
+				if got := testing.AllocsPerRun(10, func() { p.Resize(tt.capacity) }); got != 0 {
+					t.Errorf("Resize allocates %.0f times keeping the sketch, want 0", got)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCache_Trace(b *testing.B) {
+	benchmarkTrace(b, func(size uint32) Cache { return NewCache(size) })
+}
+
+func BenchmarkTinyLfuCache_Trace(b *testing.B) {
+	benchmarkTrace(b, func(size uint32) Cache { return NewTinyLfuCache(size) })
+}
@@ -0,0 +1,130 @@
+package LruCache
+
+import "container/list"
+
+// twoQueueNode links an entry to its queue, A1in or Am.
+type twoQueueNode struct {
+	element *list.Element
+	inAm    bool
+}
+
+// twoQueuePolicy is the 2Q policy.
+// The new entries land in the A1in FIFO queue, a hit doesn't move them so that correlated references are ignored.
+// The entries evicted from A1in leave their key identity in the A1out ghost queue, see keyOf.
+// An entry added while its key is in A1out lands in the Am LRU list, which holds the entries referenced again.
+// The victim is taken from A1in while it holds more than its share of the capacity, from Am otherwise.
+type twoQueuePolicy struct {
+	a1in       *list.List
+	a1out      *list.List
+	am         *list.List
+	nodes      map[keyIdentity]*twoQueueNode
+	ghosts     map[keyIdentity]*list.Element
+	inCapacity int
+	outLength  int
+}
+
+// OnInsert adds the entry to Am if its key is in A1out, to A1in otherwise.
+func (p *twoQueuePolicy) OnInsert(cacheEntry Entry) {
+	var key = keyOf(cacheEntry.Key())
+	if ghost, exists := p.ghosts[key]; exists {
+		p.a1out.Remove(ghost)
+		delete(p.ghosts, key)
+		p.nodes[key] = &twoQueueNode{element: p.am.PushFront(cacheEntry), inAm: true}
+	} else {
+		p.nodes[key] = &twoQueueNode{element: p.a1in.PushFront(cacheEntry)}
+	}
+}
+
+// OnAccess moves the entry to the front of Am, the entries of A1in don't move.
+func (p *twoQueuePolicy) OnAccess(cacheEntry Entry) {
+	if node, exists := p.nodes[keyOf(cacheEntry.Key())]; exists && node.inAm {
+		p.am.MoveToFront(node.element)
+	}
+}
+
+// OnRemove forgets the entry, its key goes to A1out when it is evicted from A1in to make room.
+func (p *twoQueuePolicy) OnRemove(cacheEntry Entry, reason EvictionReason) {
+	var key = keyOf(cacheEntry.Key())
+	var node, exists = p.nodes[key]
+	if !exists {
+		return
+	}
+	delete(p.nodes, key)
+	if node.inAm {
+		p.am.Remove(node.element)
+		return
+	}
+	p.a1in.Remove(node.element)
+	if reason == EvictionCapacity || reason == EvictionResize {
+		p.ghosts[key] = p.a1out.PushFront(key)
+		p.trimGhosts()
+	}
+}
+
+// trimGhosts drops the oldest keys of A1out beyond its length.
+func (p *twoQueuePolicy) trimGhosts() {
+	for p.a1out.Len() > p.outLength {
+		delete(p.ghosts, p.a1out.Remove(p.a1out.Back()).(keyIdentity))
+	}
+}
+
+// Victim returns the oldest entry of A1in if A1in exceeds its capacity or Am is empty,
+// the least recently used entry of Am otherwise.
+func (p *twoQueuePolicy) Victim(_ Entry) Entry {
+	if p.a1in.Len() > 0 && (p.a1in.Len() > p.inCapacity || p.am.Len() == 0) {
+		return p.a1in.Back().Value.(Entry)
+	} else if p.am.Len() > 0 {
+		return p.am.Back().Value.(Entry)
+	} else {
+		return nil
+	}
+}
+
+// Resize updates the A1in capacity and the A1out length.
+func (p *twoQueuePolicy) Resize(capacity uint32) {
+	p.inCapacity, p.outLength = twoQueueSizes(capacity)
+	p.trimGhosts()
+}
+
+// Reset forgets all the entries and the ghosts.
+func (p *twoQueuePolicy) Reset() {
+	p.a1in = list.New()
+	p.a1out = list.New()
+	p.am = list.New()
+	p.nodes = make(map[keyIdentity]*twoQueueNode)
+	p.ghosts = make(map[keyIdentity]*list.Element)
+}
+
+// twoQueueSizes returns the A1in capacity, a quarter of the cache capacity,
+// and the A1out length, half the cache capacity, both at least 1.
+func twoQueueSizes(capacity uint32) (int, int) {
+	var in, out = int(capacity / 4), int(capacity / 2)
+	if in < 1 {
+		in = 1
+	}
+	if out < 1 {
+		out = 1
+	}
+	return in, out
+}
+
+// newTwoQueuePolicy returns a new 2Q eviction policy.
+func newTwoQueuePolicy(capacity uint32) EvictionPolicy {
+	var np = new(twoQueuePolicy)
+	np.Reset()
+	np.inCapacity, np.outLength = twoQueueSizes(capacity)
+	return np
+}
+
+// WithTwoQueueEviction makes the cache use the 2Q policy: the new entries go through a FIFO queue taking a quarter
+// of the capacity, and only the entries added again shortly after their eviction reach the main LRU list.
+// The policy remembers the keys of as many evicted entries as half the cache capacity.
+func WithTwoQueueEviction() CacheOption {
+	return WithEvictionPolicy(newTwoQueuePolicy)
+}
+
+// NewTwoQueueCache returns a new cache able to store size entries, using the 2Q policy.
+// The returned cache is not safe for concurrent use, use NewSyncCache with WithTwoQueueEviction instead.
+func NewTwoQueueCache(size uint32, options ...CacheOption) Cache {
+	return NewCache(size, append(options[:len(options):len(options)], WithTwoQueueEviction())...)
+}
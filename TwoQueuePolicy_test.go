@@ -0,0 +1,126 @@
+package LruCache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_twoQueuePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity uint32
+		scenario func(c Cache)
+		a1in     []int
+		a1out    []int
+		am       []int
+	}{
+		{
+			name:     "New entries land in A1in and don't move on hits",
+			capacity: 8,
+			scenario: func(c Cache) {
+				for k := 1; k <= 3; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Get(NewIntKey(1))
+			},
+			a1in: []int{3, 2, 1}, a1out: []int{}, am: []int{},
+		},
+		{
+			name:     "The oldest entries of A1in are evicted to A1out",
+			capacity: 8,
+			scenario: func(c Cache) {
+				for k := 1; k <= 9; k++ {
+					c.Add(intEntry(k))
+				}
+			},
+			a1in: []int{9, 8, 7, 6, 5, 4, 3, 2}, a1out: []int{1}, am: []int{},
+		},
+		{
+			name:     "An entry added again from A1out lands in Am",
+			capacity: 8,
+			scenario: func(c Cache) {
+				for k := 1; k <= 9; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+			},
+			a1in: []int{9, 8, 7, 6, 5, 4}, a1out: []int{3}, am: []int{2, 1},
+		},
+		{
+			name:     "A1out length is bounded",
+			capacity: 8,
+			scenario: func(c Cache) {
+				for k := 1; k <= 20; k++ {
+					c.Add(intEntry(k))
+				}
+			},
+			a1in: []int{20, 19, 18, 17, 16, 15, 14, 13}, a1out: []int{12, 11, 10, 9}, am: []int{},
+		},
+		{
+			name:     "The Am victims are forgotten once A1in is within its capacity",
+			capacity: 4,
+			scenario: func(c Cache) {
+				for k := 1; k <= 5; k++ {
+					c.Add(intEntry(k))
+				}
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Add(intEntry(3))
+				c.Get(NewIntKey(1))
+				c.Add(intEntry(6))
+			},
+			a1in: []int{6, 5}, a1out: []int{4}, am: []int{1, 3},
+		},
+		{
+			name:     "Explicit removals don't leave ghosts",
+			capacity: 8,
+			scenario: func(c Cache) {
+				c.Add(intEntry(1))
+				c.Add(intEntry(2))
+				c.Remove(NewIntKey(1))
+				c.RemoveLruEntry()
+			},
+			a1in: []int{}, a1out: []int{}, am: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = newCache(tt.capacity, WithTwoQueueEviction())
+			tt.scenario(c)
+			var p = c.policy.(*twoQueuePolicy)
+			if got := listKeys(p.a1in); !reflect.DeepEqual(got, tt.a1in) {
+				t.Errorf("A1in = %v, want %v", got, tt.a1in)
+			}
+			if got := listKeys(p.a1out); !reflect.DeepEqual(got, tt.a1out) {
+				t.Errorf("A1out = %v, want %v", got, tt.a1out)
+			}
+			if got := listKeys(p.am); !reflect.DeepEqual(got, tt.am) {
+				t.Errorf("Am = %v, want %v", got, tt.am)
+			}
+			if len(p.ghosts) != p.a1out.Len() {
+				t.Errorf("%d ghosts indexed, want %d", len(p.ghosts), p.a1out.Len())
+			}
+		})
+	}
+}
+
+func Test_twoQueueSizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity uint32
+		wantIn   int
+		wantOut  int
+	}{
+		{name: "Tiny cache", capacity: 1, wantIn: 1, wantOut: 1},
+		{name: "Small cache", capacity: 8, wantIn: 2, wantOut: 4},
+		{name: "Large cache", capacity: 1000, wantIn: 250, wantOut: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if in, out := twoQueueSizes(tt.capacity); in != tt.wantIn || out != tt.wantOut {
+				t.Errorf("twoQueueSizes() = %d, %d, want %d, %d", in, out, tt.wantIn, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package LruCache
+
+// keyedCache is a Cache whose entries are indexed by K, it looks up the entries directly by their key identity.
+type keyedCache[K comparable] interface {
+	Cache
+	getKey(key K) Entry
+	getKeyWithoutAccessUpdate(key K) Entry
+	containsKey(key K) bool
+	removeKey(key K) (bool, Entry)
+}
+
+// typedCache is a type-safe cache object.
+// It adapts a cache indexed by the keys themselves: the entries are stored in the cache as the entries embedded
+// in the typed entries, so the typed caches share the eviction policies, the expiry index, the statistics and
+// the callbacks of the caches, and the lookups don't convert the keys.
+type typedCache[K comparable, V any] struct {
+	cache keyedCache[K]
+}
+
+// Add adds a new entry in the cache, or replaces the entry having the same key.
+// It returns what happened and the displaced entry: the replaced entry, the evicted entry, or nil.
+// The entry must be returned by NewTypedEntry.
+func (c *typedCache[K, V]) Add(cacheEntry TypedEntry[K, V]) (AddResult, TypedEntry[K, V]) {
+	var result, displaced = c.cache.Add(cacheEntry.(*typedEntry[K, V]).entry)
+	return result, typedEntryOf[K, V](displaced)
+}
+
+// Get returns the entry corresponding to the requested key. It returns nil if the entry doesn't exist or expired.
+// It updates the entry last access time and records the hit in the eviction policy.
+func (c *typedCache[K, V]) Get(key K) TypedEntry[K, V] {
+	return typedEntryOf[K, V](c.cache.getKey(key))
+}
+
+// GetWithoutAccessUpdate returns the entry corresponding to the requested key.
+// It doesn't the update the entry last access time, and doesn't move the entry in the LRU list.
+func (c *typedCache[K, V]) GetWithoutAccessUpdate(key K) TypedEntry[K, V] {
+	return typedEntryOf[K, V](c.cache.getKeyWithoutAccessUpdate(key))
+}
+
+// GetLruEntry returns the entry the eviction policy would evict next, the oldest cache entry by default.
+// It doesn't the update the entry last access time.
+func (c *typedCache[K, V]) GetLruEntry() TypedEntry[K, V] {
+	return typedEntryOf[K, V](c.cache.GetLruEntry())
+}
+
+// Contains returns true if the cache contains an entry for the requested key.
+func (c *typedCache[K, V]) Contains(key K) bool {
+	return c.cache.containsKey(key)
+}
+
+// Remove removes the cache entry corresponding the requested key.
+// It returns true if the entry exists, and the removed entry.
+func (c *typedCache[K, V]) Remove(key K) (bool, TypedEntry[K, V]) {
+	var removed, cacheEntry = c.cache.removeKey(key)
+	return removed, typedEntryOf[K, V](cacheEntry)
+}
+
+// RemoveLruEntry removes the entry the eviction policy would evict next, the least recently used one by default, and returns it.
+func (c *typedCache[K, V]) RemoveLruEntry() TypedEntry[K, V] {
+	return typedEntryOf[K, V](c.cache.RemoveLruEntry())
+}
+
+// Keys returns the list of cache entries keys.
+func (c *typedCache[K, V]) Keys() []K {
+	var entryKeys = c.cache.Keys()
+	var keys = make([]K, len(entryKeys))
+	for i, k := range entryKeys {
+		keys[i] = typedKeyOf[K](k)
+	}
+	return keys
+}
+
+// Len returns the number of entries present in the cache.
+func (c *typedCache[K, V]) Len() uint32 {
+	return c.cache.Len()
+}
+
+// Capacity returns the cache capacity.
+func (c *typedCache[K, V]) Capacity() uint32 {
+	return c.cache.Capacity()
+}
+
+// Flush clears the cache and returns the number of entries flushed.
+func (c *typedCache[K, V]) Flush() uint32 {
+	return c.cache.Flush()
+}
+
+// Resize updates the cache capacity and returns the number of cache entries flushed during the downsizing and the slice of flushed entries.
+func (c *typedCache[K, V]) Resize(size uint32) (uint32, []TypedEntry[K, V]) {
+	var numberOfDeletion, flushedEntries = c.cache.Resize(size)
+	return numberOfDeletion, typedEntries[K, V](flushedEntries)
+}
+
+// HouseCleaning triggers the cache cleaning and removes the entries expired.
+// It returns the number of flushed entries and the slice of them.
+func (c *typedCache[K, V]) HouseCleaning() (uint32, []TypedEntry[K, V]) {
+	var numberOfDeletions, flushedEntries = c.cache.HouseCleaning()
+	return numberOfDeletions, typedEntries[K, V](flushedEntries)
+}
+
+// IsFull returns true if the cache reaches its maximum capacity
+func (c *typedCache[K, V]) IsFull() bool {
+	return c.cache.IsFull()
+}
+
+// Stats returns the cache statistics.
+func (c *typedCache[K, V]) Stats() Stats {
+	return c.cache.Stats()
+}
+
+// ResetStats sets the cache statistics to zero.
+func (c *typedCache[K, V]) ResetStats() {
+	c.cache.ResetStats()
+}
+
+// typedEntries returns the typed entries holding the cache entries.
+func typedEntries[K comparable, V any](cacheEntries []Entry) []TypedEntry[K, V] {
+	var entries = make([]TypedEntry[K, V], len(cacheEntries))
+	for i, cacheEntry := range cacheEntries {
+		entries[i] = typedEntryOf[K, V](cacheEntry)
+	}
+	return entries
+}
+
+// OnTypedEvict sets the callback called each time an entry leaves a typed cache, with the reason why.
+// It is the OnEvict option receiving the typed entries, the cache must only hold entries of type TypedEntry[K, V].
+func OnTypedEvict[K comparable, V any](callback func(TypedEntry[K, V], EvictionReason)) CacheOption {
+	return OnEvict(func(cacheEntry Entry, reason EvictionReason) {
+		callback(typedEntryOf[K, V](cacheEntry), reason)
+	})
+}
+
+// NewTypedCache returns a new type-safe cache able to store size entries.
+// It accepts the options of NewCache. The returned cache is not safe for concurrent use, see NewTypedSyncCache.
+func NewTypedCache[K comparable, V any](size uint32, options ...CacheOption) TypedCache[K, V] {
+	return &typedCache[K, V]{cache: newKeyedCache(size, typedKeyOf[K], options)}
+}
+
+// NewTypedSyncCache returns a new type-safe cache safe for concurrent use, able to store size entries.
+func NewTypedSyncCache[K comparable, V any](size uint32, options ...CacheOption) TypedCache[K, V] {
+	return &typedCache[K, V]{cache: newSyncCache(newKeyedCache(size, typedKeyOf[K], options))}
+}
+
+// NewTypedShardedCache returns a new type-safe cache safe for concurrent use, able to store capacity entries
+// spread over the requested number of shards, see NewShardedCache.
+func NewTypedShardedCache[K comparable, V any](shards uint32, capacity uint32, options ...CacheOption) TypedCache[K, V] {
+	return &typedCache[K, V]{cache: newShardedCache(shards, capacity, typedKeyOf[K], options)}
+}
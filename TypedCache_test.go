@@ -0,0 +1,321 @@
+package LruCache
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mmaFR/LruCache/clocktest"
+)
+
+type testTypedKey struct {
+	tenant string
+	id     int
+}
+
+func TestNewTypedCache(t *testing.T) {
+	tests := []struct {
+		name string
+		size uint32
+		want uint32
+	}{
+		{
+			name: "Create typed cache",
+			size: 128,
+			want: 128,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewTypedCache[string, int](tt.size)
+			if got.Capacity() != tt.want {
+				t.Errorf("New typed cache capacity = %d, want %d", got.Capacity(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTypedEntry(t *testing.T) {
+	var e = NewTypedEntry(testTypedKey{tenant: "A", id: 1}, []string{"A", "entry"}, Second(10), Second(15))
+	if got := e.Key(); got != (testTypedKey{tenant: "A", id: 1}) {
+		t.Errorf("Key() = %v, want {A 1}", got)
+	}
+	if got := e.Value(); !reflect.DeepEqual(got, []string{"A", "entry"}) {
+		t.Errorf("Value() = %v, want [A entry]", got)
+	}
+	if got := e.GetTTL(); got != Second(10) {
+		t.Errorf("GetTTL() = %v, want %v", got, Second(10))
+	}
+	if got := e.GetMaxAge(); got != Second(15) {
+		t.Errorf("GetMaxAge() = %v, want %v", got, Second(15))
+	}
+	if e.IsExpired() {
+		t.Error("New entry is expired and it should not.")
+	}
+}
+
+func Test_typedCache_Add(t *testing.T) {
+	var e1a = NewTypedEntry("A", 1, Second(10), Second(15))
+	var e1b = NewTypedEntry("B", 2, Second(10), Second(15))
+	var e1c = NewTypedEntry("C", 3, Second(10), Second(15))
+	var e1aBis = NewTypedEntry("A", 10, Second(10), Second(15))
+
+	tests := []struct {
+		name     string
+		capacity uint32
+		entries  []TypedEntry[string, int]
+		want     AddResult
+		want1    TypedEntry[string, int]
+		want2    uint32
+	}{
+		{
+			name:     "Add an entry in an empty cache",
+			capacity: 2,
+			entries:  []TypedEntry[string, int]{e1a},
+			want:     EntryInserted,
+			want1:    nil,
+			want2:    1,
+		},
+		{
+			name:     "Add an entry in a full cache",
+			capacity: 2,
+			entries:  []TypedEntry[string, int]{e1a, e1b, e1c},
+			want:     LruEntryEvicted,
+			want1:    e1a,
+			want2:    2,
+		},
+		{
+			name:     "Overwrite an entry in a full cache",
+			capacity: 2,
+			entries:  []TypedEntry[string, int]{e1a, e1b, e1aBis},
+			want:     EntryReplaced,
+			want1:    e1a,
+			want2:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c = NewTypedCache[string, int](tt.capacity)
+			var got AddResult
+			var got1 TypedEntry[string, int]
+			for _, e := range tt.entries {
+				got, got1 = c.Add(e)
+			}
+			if got != tt.want {
+				t.Errorf("Got %s in return instead of %s", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("Got %v as displaced entry instead of %v", got1, tt.want1)
+			}
+			if l := c.Len(); l != tt.want2 {
+				t.Errorf("Cache len = %d, want %d", l, tt.want2)
+			}
+			if l := uint32(c.(*typedCache[string, int]).cache.(*cache[string]).policy.(*lruPolicy).list.Len()); l != tt.want2 {
+				t.Errorf("LRU list len = %d, want %d", l, tt.want2)
+			}
+			var last = tt.entries[len(tt.entries)-1]
+			if got := c.GetWithoutAccessUpdate(last.Key()); got != last {
+				t.Errorf("GetWithoutAccessUpdate() = %v, want %v", got, last)
+			}
+		})
+	}
+}
+
+func Test_typedCache_Get(t *testing.T) {
+	var c = NewTypedCache[testTypedKey, string](4)
+	c.Add(NewTypedEntry(testTypedKey{tenant: "A", id: 1}, "A1", Second(10), Second(15)))
+	c.Add(NewTypedEntry(testTypedKey{tenant: "B", id: 1}, "B1", Second(10), Second(15)))
+	c.Add(NewTypedEntry(testTypedKey{tenant: "A", id: 2}, "A2", Second(10), Second(15)))
+
+	if got := c.Get(testTypedKey{tenant: "A", id: 1}); got == nil || got.Value() != "A1" {
+		t.Errorf("Get() = %v, want A1", got)
+	}
+	if got := c.Get(testTypedKey{tenant: "C", id: 1}); got != nil {
+		t.Errorf("Get() = %v, want nil", got)
+	}
+	if got := c.GetLruEntry(); got == nil || got.Value() != "B1" {
+		t.Errorf("GetLruEntry() = %v, want B1", got)
+	}
+	if got := c.RemoveLruEntry(); got == nil || got.Value() != "B1" {
+		t.Errorf("RemoveLruEntry() = %v, want B1", got)
+	}
+	if got := c.GetLruEntry(); got == nil || got.Value() != "A2" {
+		t.Errorf("GetLruEntry() = %v, want A2", got)
+	}
+}
+
+func Test_typedCache_Expiry(t *testing.T) {
+	var clock = clocktest.NewFakeClock(time.Now())
+	var c = NewTypedCache[int, string](4, WithClock(clock))
+	c.Add(NewTypedEntry(1, "expired", Second(1), Second(15), WithEntryClock(clock)))
+	c.Add(NewTypedEntry(2, "alive", Second(10), Second(15), WithEntryClock(clock)))
+	c.Add(NewTypedEntry(3, "expired", Second(1), Second(15), WithEntryClock(clock)))
+	clock.Advance(Second(2))
+
+	if got := c.Get(1); got != nil {
+		t.Errorf("Get() = %v, want nil", got)
+	}
+	if c.Contains(1) {
+		t.Error("The expired entry should have been removed by Get")
+	}
+	got, got1 := c.HouseCleaning()
+	if got != 1 || len(got1) != 1 || got1[0].Key() != 3 {
+		t.Errorf("HouseCleaning() = %d, %v, want 1, [3]", got, got1)
+	}
+	if got := c.Keys(); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Keys() = %v, want [2]", got)
+	}
+}
+
+func Test_typedCache_Resize(t *testing.T) {
+	var c = NewTypedCache[int, int](4)
+	for i := 0; i < 4; i++ {
+		c.Add(NewTypedEntry(i, i, Second(10), Second(15)))
+	}
+	if !c.IsFull() {
+		t.Error("The cache should be full")
+	}
+	got, got1 := c.Resize(2)
+	if got != 2 || len(got1) != 2 || got1[0].Key() != 0 || got1[1].Key() != 1 {
+		t.Errorf("Resize() = %d, %v, want 2, [0 1]", got, got1)
+	}
+	var keys = c.Keys()
+	sort.Ints(keys)
+	if !reflect.DeepEqual(keys, []int{2, 3}) {
+		t.Errorf("Keys() = %v, want [2 3]", keys)
+	}
+	if got := c.Flush(); got != 2 {
+		t.Errorf("Flush() = %d, want 2", got)
+	}
+	if got := c.Len(); got != 0 {
+		t.Errorf("Len() = %d, want 0", got)
+	}
+}
+
+func Test_typedCache_SharedCore(t *testing.T) {
+	var clock = clocktest.NewFakeClock(time.Now())
+	var evicted []string
+	var c = NewTypedCache[int, string](2, WithClock(clock), WithLfuEviction(),
+		OnTypedEvict(func(e TypedEntry[int, string], reason EvictionReason) {
+			evicted = append(evicted, fmt.Sprintf("%d %s", e.Key(), reason))
+		}))
+	c.Add(NewTypedEntry(1, "one", NoExpiration, NoExpiration, WithEntryClock(clock)))
+	c.Add(NewTypedEntry(2, "two", NoExpiration, NoExpiration, WithEntryClock(clock)))
+	c.Get(1)
+	c.Get(1)
+	c.Get(3)
+	c.Add(NewTypedEntry(3, "three", NoExpiration, NoExpiration, WithEntryClock(clock)))
+	var got = c.Get(3)
+	if got == nil {
+		t.Fatal("Get(3) = nil, want the added entry")
+	}
+	got.SetTTL(Second(1))
+	clock.Advance(Second(2))
+	if n, _ := c.HouseCleaning(); n != 1 {
+		t.Errorf("HouseCleaning() = %d, want 1 once the TTL of the entry was shortened", n)
+	}
+	var want = []string{fmt.Sprintf("2 %s", EvictionCapacity), fmt.Sprintf("3 %s", EvictionTTL)}
+	if !reflect.DeepEqual(evicted, want) {
+		t.Errorf("Evicted entries = %v, want %v", evicted, want)
+	}
+	if s := c.Stats(); s.Hits != 3 || s.Misses != 1 || s.CapacityEvictions != 1 {
+		t.Errorf("Stats() = %+v, want 3 hits, 1 miss and 1 capacity eviction", s)
+	}
+}
+
+func Test_typedCache_Concurrent(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache TypedCache[int, int]
+	}{
+		{name: "Sync cache", cache: NewTypedSyncCache[int, int](64)},
+		{name: "Sharded cache", cache: NewTypedShardedCache[int, int](4, 64)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 500; i++ {
+						var k = (g*500 + i) % 96
+						if e := tt.cache.Get(k); e == nil {
+							tt.cache.Add(NewTypedEntry(k, k, NoExpiration, NoExpiration))
+						} else if e.Value() != k {
+							t.Errorf("Get(%d) = %d", k, e.Value())
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			if l := tt.cache.Len(); l != 64 {
+				t.Errorf("Len() = %d, want 64", l)
+			}
+			if s := tt.cache.Stats(); s.Hits+s.Misses != 4000 {
+				t.Errorf("%d hits and %d misses, want 4000 lookups", s.Hits, s.Misses)
+			}
+		})
+	}
+}
+
+func TestStartTypedJanitor(t *testing.T) {
+	var c = NewTypedSyncCache[string, int](16)
+	var reports = make(chan []TypedEntry[string, int], 16)
+	c.Add(NewTypedEntry("A", 1, time.Millisecond, NoExpiration))
+	c.Add(NewTypedEntry("B", 2, Second(10), NoExpiration))
+	var j = StartTypedJanitor(c, 2*time.Millisecond, func(_ uint32, entries []TypedEntry[string, int]) {
+		reports <- entries
+	})
+	defer j.Stop()
+	select {
+	case entries := <-reports:
+		if len(entries) != 1 || entries[0].Key() != "A" {
+			t.Errorf("The janitor cleaned %v, want A", entries)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("The janitor didn't clean the expired entry")
+	}
+}
+
+func TestTypedCache_LookupAllocations(t *testing.T) {
+	var stringCache = NewTypedCache[string, int](16, WithTinyLfuEviction())
+	stringCache.Add(NewTypedEntry("A", 1, NoExpiration, NoExpiration))
+	var structCache = NewTypedShardedCache[testTypedKey, int](4, 16)
+	var structKey = testTypedKey{tenant: "A", id: 1}
+	structCache.Add(NewTypedEntry(structKey, 1, NoExpiration, NoExpiration))
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{name: "Get with a string key", f: func() { stringCache.Get("A") }},
+		{name: "GetWithoutAccessUpdate", f: func() { stringCache.GetWithoutAccessUpdate("A") }},
+		{name: "Contains", f: func() { stringCache.Contains("A") }},
+		{name: "sharded Get with a struct key", f: func() { structCache.Get(structKey) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testing.AllocsPerRun(100, tt.f); got != 0 {
+				t.Errorf("%s allocates %.0f times, want 0", tt.name, got)
+			}
+		})
+	}
+}
+
+func BenchmarkTypedCache_Get(b *testing.B) {
+	var c = NewTypedCache[string, int](1024)
+	var keys = make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		c.Add(NewTypedEntry(keys[i], i, NoExpiration, NoExpiration))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(keys[i%len(keys)])
+	}
+}
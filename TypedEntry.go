@@ -0,0 +1,67 @@
+package LruCache
+
+import (
+	"fmt"
+	"time"
+)
+
+// typedKey is the EntryKey wrapping the key of a typed entry in the caches.
+// The typed caches index it by the key itself, see typedKeyOf. The eviction policies index it by its identity,
+// boxed once when the entry is created so that indexing it doesn't allocate.
+type typedKey[K comparable] struct {
+	key      K
+	identity interface{}
+}
+
+// String returns the key string representation.
+func (k typedKey[K]) String() string {
+	return fmt.Sprint(k.key)
+}
+
+// Identity returns the key itself.
+func (k typedKey[K]) Identity() interface{} {
+	return k.identity
+}
+
+// typedKeyOf returns the key of a typed entry key, it indexes the entries in the typed caches.
+func typedKeyOf[K comparable](key EntryKey) K {
+	return key.(typedKey[K]).key
+}
+
+// typedEntry is a type-safe cache entry object.
+// The embedded entry is the one stored in the caches, its key is a typedKey and its value the typedEntry itself.
+type typedEntry[K comparable, V any] struct {
+	*entry
+	key   K
+	value V
+}
+
+// Key returns the entry key.
+func (e *typedEntry[K, V]) Key() K {
+	return e.key
+}
+
+// Value returns the entry value and updates the entry last access time.
+// It doesn't move the entry in the cache LRU list, use TypedCache.Get for that.
+func (e *typedEntry[K, V]) Value() V {
+	e.UpdateAccessTime()
+	return e.value
+}
+
+// typedEntryOf returns the typed entry holding the cache entry, or nil.
+func typedEntryOf[K comparable, V any](cacheEntry Entry) TypedEntry[K, V] {
+	if cacheEntry == nil {
+		return nil
+	}
+	return cacheEntry.(*entry).value.(*typedEntry[K, V])
+}
+
+// NewTypedEntry returns a new type-safe cache entry.
+// The entry uses the SystemClock unless the WithEntryClock option is given.
+func NewTypedEntry[K comparable, V any](key K, value V, ttl time.Duration, maxAge time.Duration, options ...EntryOption) TypedEntry[K, V] {
+	var ne = new(typedEntry[K, V])
+	ne.entry = NewEntry(typedKey[K]{key: key, identity: key}, ne, ttl, maxAge, options...).(*entry)
+	ne.key = key
+	ne.value = value
+	return ne
+}
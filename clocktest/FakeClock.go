@@ -0,0 +1,42 @@
+// Package clocktest provides a manually advanced clock to test the cache expiry deterministically.
+package clocktest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock whose time only changes when Advance or Set is called.
+// It implements the LruCache.Clock interface and is safe for concurrent use.
+type FakeClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// Now returns the fake clock current time.
+func (fc *FakeClock) Now() time.Time {
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+	return fc.now
+}
+
+// Advance moves the fake clock current time forward by d.
+func (fc *FakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	fc.now = fc.now.Add(d)
+	fc.mu.Unlock()
+}
+
+// Set sets the fake clock current time to t.
+func (fc *FakeClock) Set(t time.Time) {
+	fc.mu.Lock()
+	fc.now = t
+	fc.mu.Unlock()
+}
+
+// NewFakeClock returns a new fake clock whose current time is start.
+func NewFakeClock(start time.Time) *FakeClock {
+	var fc = new(FakeClock)
+	fc.now = start
+	return fc
+}
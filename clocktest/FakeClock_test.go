@@ -0,0 +1,22 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	var start = time.Date(2021, 12, 15, 0, 0, 0, 0, time.UTC)
+	var fc = NewFakeClock(start)
+	if got := fc.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, want %v", got, start)
+	}
+	fc.Advance(time.Minute)
+	if got := fc.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("Now() = %v after Advance, want %v", got, start.Add(time.Minute))
+	}
+	fc.Set(start)
+	if got := fc.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v after Set, want %v", got, start)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/mmaFR/LruCache
 
-go 1.17
+go 1.24
@@ -0,0 +1,203 @@
+// Package promexport exposes the statistics of LruCache caches in the Prometheus text exposition format,
+// without depending on the Prometheus client library.
+package promexport
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mmaFR/LruCache"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// ErrDuplicateName is returned by Register when a cache is already registered with the same name.
+var ErrDuplicateName = errors.New("promexport: a cache is already registered with this name")
+
+// Exporter is the interface of the HTTP handler exposing the registered caches metrics.
+type Exporter interface {
+	http.Handler
+
+	// Register adds the cache to the exported caches, its metrics are labelled with name.
+	// It returns ErrDuplicateName if a cache is already registered with the same name.
+	Register(name string, c LruCache.Cache) error
+
+	// Unregister removes the cache registered with name from the exported caches.
+	Unregister(name string)
+
+	// WriteMetrics writes the metrics of the registered caches to w.
+	WriteMetrics(w io.Writer) error
+}
+
+// metric is an exported metric family.
+type metric struct {
+	name       string
+	help       string
+	metricType string
+	samples    func(s LruCache.Stats, c LruCache.Cache) []sample
+}
+
+// sample is a metric value, with an optional reason label.
+type sample struct {
+	reason string
+	value  uint64
+}
+
+// metrics are the exported metric families, in the exposition order.
+var metrics = []metric{
+	{
+		name:       "lrucache_entries",
+		help:       "Number of entries present in the cache.",
+		metricType: "gauge",
+		samples: func(_ LruCache.Stats, c LruCache.Cache) []sample {
+			return []sample{{value: uint64(c.Len())}}
+		},
+	},
+	{
+		name:       "lrucache_capacity",
+		help:       "Maximum number of entries of the cache.",
+		metricType: "gauge",
+		samples: func(_ LruCache.Stats, c LruCache.Cache) []sample {
+			return []sample{{value: uint64(c.Capacity())}}
+		},
+	},
+	{
+		name:       "lrucache_hits_total",
+		help:       "Number of lookups which found a valid entry.",
+		metricType: "counter",
+		samples: func(s LruCache.Stats, _ LruCache.Cache) []sample {
+			return []sample{{value: s.Hits}}
+		},
+	},
+	{
+		name:       "lrucache_misses_total",
+		help:       "Number of lookups which didn't find a valid entry.",
+		metricType: "counter",
+		samples: func(s LruCache.Stats, _ LruCache.Cache) []sample {
+			return []sample{{value: s.Misses}}
+		},
+	},
+	{
+		name:       "lrucache_inserts_total",
+		help:       "Number of entries added for a new key.",
+		metricType: "counter",
+		samples: func(s LruCache.Stats, _ LruCache.Cache) []sample {
+			return []sample{{value: s.Inserts}}
+		},
+	},
+	{
+		name:       "lrucache_replacements_total",
+		help:       "Number of entries added in place of an entry having the same key.",
+		metricType: "counter",
+		samples: func(s LruCache.Stats, _ LruCache.Cache) []sample {
+			return []sample{{value: s.Replacements}}
+		},
+	},
+	{
+		name:       "lrucache_flushes_total",
+		help:       "Number of cache flushes.",
+		metricType: "counter",
+		samples: func(s LruCache.Stats, _ LruCache.Cache) []sample {
+			return []sample{{value: s.Flushes}}
+		},
+	},
+	{
+		name:       "lrucache_evictions_total",
+		help:       "Number of entries removed by the cache, by reason.",
+		metricType: "counter",
+		samples: func(s LruCache.Stats, _ LruCache.Cache) []sample {
+			return []sample{
+				{reason: "capacity", value: s.CapacityEvictions},
+				{reason: "expired_on_read", value: s.ExpiredOnRead},
+				{reason: "house_cleaning", value: s.HouseCleaningRemovals},
+				{reason: "resize", value: s.ResizeEvictions},
+				{reason: "flush", value: s.FlushedEntries},
+			}
+		},
+	},
+}
+
+// exporter is the Prometheus exporter object.
+type exporter struct {
+	mu     sync.RWMutex
+	caches map[string]LruCache.Cache
+}
+
+// Register adds the cache to the exported caches, its metrics are labelled with name.
+// It returns ErrDuplicateName if a cache is already registered with the same name.
+func (e *exporter) Register(name string, c LruCache.Cache) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, exists := e.caches[name]; exists {
+		return ErrDuplicateName
+	}
+	e.caches[name] = c
+	return nil
+}
+
+// Unregister removes the cache registered with name from the exported caches.
+func (e *exporter) Unregister(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.caches, name)
+}
+
+// WriteMetrics writes the metrics of the registered caches to w, sorted by cache name.
+func (e *exporter) WriteMetrics(w io.Writer) error {
+	e.mu.RLock()
+	var names = make([]string, 0, len(e.caches))
+	for name := range e.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var caches = make([]LruCache.Cache, len(names))
+	var stats = make([]LruCache.Stats, len(names))
+	for i, name := range names {
+		caches[i] = e.caches[name]
+		stats[i] = caches[i].Stats()
+	}
+	e.mu.RUnlock()
+
+	var bw = bufio.NewWriter(w)
+	for _, m := range metrics {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.metricType)
+		for i, name := range names {
+			for _, s := range m.samples(stats[i], caches[i]) {
+				if s.reason == "" {
+					fmt.Fprintf(bw, "%s{cache=\"%s\"} %d\n", m.name, escapeLabelValue(name), s.value)
+				} else {
+					fmt.Fprintf(bw, "%s{cache=\"%s\",reason=\"%s\"} %d\n", m.name, escapeLabelValue(name), s.reason, s.value)
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP writes the metrics of the registered caches in the HTTP response.
+func (e *exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_ = e.WriteMetrics(w)
+}
+
+// labelValueEscaper escapes the backslash, the double quote and the line feed in a label value.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue returns the label value escaped for the text exposition format.
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// NewExporter returns a new exporter without registered cache.
+func NewExporter() Exporter {
+	var ne = new(exporter)
+	ne.caches = make(map[string]LruCache.Cache)
+	return ne
+}
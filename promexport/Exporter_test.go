@@ -0,0 +1,128 @@
+package promexport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmaFR/LruCache"
+)
+
+func newEntry(key string) LruCache.Entry {
+	return LruCache.NewEntry(LruCache.NewStringKey(key), key+" entry", time.Minute, time.Hour)
+}
+
+func TestExporter_ServeHTTP(t *testing.T) {
+	var users = LruCache.NewSyncCache(2)
+	users.Add(newEntry("A"))
+	users.Add(newEntry("B"))
+	users.Add(newEntry("C"))
+	users.Get(LruCache.NewStringKey("C"))
+	users.Get(LruCache.NewStringKey("A"))
+	var sessions = LruCache.NewCache(8)
+	sessions.Add(newEntry("A"))
+	sessions.Flush()
+
+	var e = NewExporter()
+	if err := e.Register("users", users); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := e.Register("sessions", sessions); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	var server = httptest.NewServer(e)
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %s, want %s", got, ContentType)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	var want = []string{
+		"# HELP lrucache_entries Number of entries present in the cache.",
+		"# TYPE lrucache_entries gauge",
+		`lrucache_entries{cache="sessions"} 0`,
+		`lrucache_entries{cache="users"} 2`,
+		"# TYPE lrucache_capacity gauge",
+		`lrucache_capacity{cache="sessions"} 8`,
+		`lrucache_capacity{cache="users"} 2`,
+		"# TYPE lrucache_hits_total counter",
+		`lrucache_hits_total{cache="users"} 1`,
+		`lrucache_misses_total{cache="users"} 1`,
+		`lrucache_inserts_total{cache="users"} 3`,
+		`lrucache_replacements_total{cache="users"} 0`,
+		`lrucache_flushes_total{cache="sessions"} 1`,
+		"# TYPE lrucache_evictions_total counter",
+		`lrucache_evictions_total{cache="users",reason="capacity"} 1`,
+		`lrucache_evictions_total{cache="users",reason="expired_on_read"} 0`,
+		`lrucache_evictions_total{cache="sessions",reason="flush"} 1`,
+	}
+	var lines = strings.Split(string(body), "\n")
+	for _, w := range want {
+		var found bool
+		for _, l := range lines {
+			if l == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Line %q not found in:\n%s", w, body)
+		}
+	}
+	// The caches are sorted by name
+	if strings.Index(string(body), `lrucache_entries{cache="sessions"}`) > strings.Index(string(body), `lrucache_entries{cache="users"}`) {
+		t.Error("The caches metrics are not sorted by name")
+	}
+}
+
+func TestExporter_Register(t *testing.T) {
+	var e = NewExporter()
+	var c = LruCache.NewCache(4)
+	if err := e.Register("cache", c); err != nil {
+		t.Errorf("Register() error = %v, want nil", err)
+	}
+	if err := e.Register("cache", c); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("Register() error = %v, want %v", err, ErrDuplicateName)
+	}
+	e.Unregister("cache")
+	var sb strings.Builder
+	if err := e.WriteMetrics(&sb); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+	if strings.Contains(sb.String(), `cache="cache"`) {
+		t.Error("The unregistered cache is still exported")
+	}
+	if err := e.Register("cache", c); err != nil {
+		t.Errorf("Register() error = %v after Unregister(), want nil", err)
+	}
+}
+
+func Test_escapeLabelValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "Plain value", value: "users", want: "users"},
+		{name: "Double quote", value: `a"b`, want: `a\"b`},
+		{name: "Backslash", value: `a\b`, want: `a\\b`},
+		{name: "Line feed", value: "a\nb", want: `a\nb`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeLabelValue(tt.value); got != tt.want {
+				t.Errorf("escapeLabelValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}